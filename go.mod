@@ -5,6 +5,7 @@ go 1.23
 require (
 	github.com/gookit/goutil v0.8.0
 	github.com/monoculum/formam v3.5.5+incompatible
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,608 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"github.com/gookit/goutil/x/ccolor"
+)
+
+// IssueKind classifies a RouteIssue found by Router.Analyze.
+type IssueKind uint8
+
+const (
+	// IssueAmbiguous reports two dynamic routes matching the same request
+	// path. The lookup priorities pick one, both stay reachable.
+	IssueAmbiguous IssueKind = iota + 1
+	// IssueShadowed reports a route whose every sample path is served by
+	// other routes.
+	IssueShadowed
+	// IssueUnreachable reports a route no request can be dispatched to,
+	// whatever the other routes.
+	IssueUnreachable
+)
+
+// String returns the kind name.
+func (k IssueKind) String() string {
+	switch k {
+	case IssueAmbiguous:
+		return "ambiguous"
+	case IssueShadowed:
+		return "shadowed"
+	case IssueUnreachable:
+		return "unreachable"
+	}
+	return "unknown"
+}
+
+// RouteSample is a request path and the route it is dispatched to.
+type RouteSample struct {
+	Path string
+	// Winner is nil if no route serves Path.
+	Winner *Route
+}
+
+// RouteIssue is a finding of Router.Analyze.
+type RouteIssue struct {
+	Kind    IssueKind
+	Methods []string
+	// Host is the host pattern the issue applies to, "" for host-less
+	// routes on any host.
+	Host string
+	// Path is the analyzed route path: an optional-segment expansion, with
+	// the mount prefix for routes of mounted routers.
+	Path  string
+	Route *Route
+	// Other is the second route of an ambiguous pair.
+	Other *Route
+	// Samples are the request paths the finding is based on, with the
+	// route serving each.
+	Samples []RouteSample
+	Reason  string
+}
+
+// String returns a one-line description of the issue.
+func (i RouteIssue) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s %s%s", i.Kind, strings.Join(i.Methods, ","), i.Host, i.Path)
+	if i.Other != nil {
+		b.WriteString(" and " + i.Other.path)
+	}
+	if i.Reason != "" {
+		b.WriteString(" (" + i.Reason + ")")
+	}
+	for n, s := range i.Samples {
+		sep := ", "
+		if n == 0 {
+			sep = ": "
+		}
+		winner := "no route"
+		if s.Winner != nil {
+			winner = s.Winner.path
+		}
+		b.WriteString(sep + s.Path + " -> " + winner)
+	}
+	return b.String()
+}
+
+// Analysis is the report of Router.Analyze.
+type Analysis struct {
+	Issues []RouteIssue
+}
+
+// OK reports whether no issue was found.
+func (a *Analysis) OK() bool { return len(a.Issues) == 0 }
+
+// Filter returns the issues of the given kinds, all issues if none given.
+func (a *Analysis) Filter(kinds ...IssueKind) []RouteIssue {
+	if len(kinds) == 0 {
+		return a.Issues
+	}
+	var out []RouteIssue
+	for _, issue := range a.Issues {
+		for _, k := range kinds {
+			if issue.Kind == k {
+				out = append(out, issue)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Err returns an error listing the issues of the given kinds (all if none
+// given), or nil if there are none. Handy as a test assertion:
+//
+//	if err := r.Analyze().Err(rux.IssueShadowed, rux.IssueUnreachable); err != nil {
+//		t.Fatal(err)
+//	}
+func (a *Analysis) Err(kinds ...IssueKind) error {
+	issues := a.Filter(kinds...)
+	if len(issues) == 0 {
+		return nil
+	}
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = issue.String()
+	}
+	return errors.New("rux: route analysis found issues:\n  " + strings.Join(lines, "\n  "))
+}
+
+// String returns one line per issue.
+func (a *Analysis) String() string {
+	var b strings.Builder
+	for _, issue := range a.Issues {
+		b.WriteString(issue.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Analyze reports ambiguous, shadowed and unreachable routes, freezing
+// the router first. It checks sample request paths built from each route
+// pattern against the route tables, so findings are exact for the samples
+// but not a proof for every path.
+//
+// Reported are overlapping dynamic routes of a method, routes whose
+// samples are all served elsewhere (e.g. a constrained param registered
+// after a broader one, a host-less route under a host catch-all, a route
+// of a mounted router under a parent route), and routes no request
+// reaches (an unsatisfiable constraint, InterceptAll). Static routes
+// beating dynamic ones are by design and not reported.
+//
+// The analysis is quadratic in the number of routes; it only runs when
+// called, e.g. from a test or at startup, and in debug mode, where Freeze
+// prints the issues as warnings:
+//
+//	if a := r.Analyze(); !a.OK() {
+//		log.Print(a)
+//	}
+func (r *Router) Analyze() *Analysis {
+	if rt := r.current(); rt != r {
+		return rt.Analyze()
+	}
+	if !r.frozen.Load() {
+		r.Freeze()
+	}
+	return &Analysis{Issues: r.analyze("")}
+}
+
+// warnIssues prints the Analyze issues of r, for debug mode.
+func (r *Router) warnIssues() {
+	for _, issue := range r.analyze("") {
+		ccolor.Warnln("rux: route " + issue.String())
+	}
+}
+
+// analyzeEntry is a route as stored in a route table: the route itself or
+// one of its optional-segment expansions.
+type analyzeEntry struct {
+	route   *Route
+	owner   *Route
+	table   *routeTable
+	host    string
+	samples []string
+	// tree holds the entry alone, to test if a path matches its pattern.
+	tree *radixTree
+}
+
+// analyze returns the issues of r's own route set. prefix is prepended to
+// paths, for mounted routers.
+func (r *Router) analyze(prefix string) []RouteIssue {
+	var entries []*analyzeEntry
+	owners := make(map[*Route]*Route)
+	for _, route := range r.routeList {
+		stored := []*Route{route}
+		if len(route.expansions) > 0 {
+			stored = route.expansions
+		}
+		for _, s := range stored {
+			owners[s] = route
+			e := &analyzeEntry{route: s, owner: route, table: &r.routeTable, host: s.host}
+			if s.host != "" {
+				e.table = &r.lookupHostPattern(s.host).routeTable
+			}
+			e.samples = samplePaths(s.path)
+			entries = append(entries, e)
+		}
+	}
+
+	var issues []RouteIssue
+	add := func(issue RouteIssue, method string) {
+		issue.Path = prefix + issue.Path
+		for i := range issue.Samples {
+			issue.Samples[i].Path = prefix + issue.Samples[i].Path
+			if w := owners[issue.Samples[i].Winner]; w != nil {
+				issue.Samples[i].Winner = w
+			}
+		}
+		// Merge the same finding for several methods of a route.
+		for i := range issues {
+			if sameIssue(issues[i], issue) {
+				issues[i].Methods = append(issues[i].Methods, method)
+				return
+			}
+		}
+		issue.Methods = []string{method}
+		issues = append(issues, issue)
+	}
+
+	if r.interceptAll != "" {
+		for _, e := range entries {
+			for _, m := range e.route.methods {
+				if served(e.table, r.methods.index(m), r.interceptAll, e.route) != e.route {
+					add(RouteIssue{Kind: IssueUnreachable, Host: e.host, Path: e.route.path, Route: e.owner,
+						Reason: "InterceptAll " + r.interceptAll}, m)
+				}
+			}
+		}
+		return issues
+	}
+
+	shadowed := make(map[*analyzeEntry]bool)
+	for _, e := range entries {
+		for _, m := range e.route.methods {
+			if len(e.samples) == 0 {
+				add(RouteIssue{Kind: IssueUnreachable, Host: e.host, Path: e.route.path, Route: e.owner,
+					Reason: "no request path matches the pattern"}, m)
+				continue
+			}
+			if samples, lost := e.resolve(e.table, r.methods.index(m), e.samples); lost {
+				shadowed[e] = true
+				add(RouteIssue{Kind: IssueShadowed, Host: e.host, Path: e.route.path, Route: e.owner, Samples: samples}, m)
+			}
+		}
+	}
+
+	// Host-less routes under a host route of another pattern, e.g. a
+	// host catch-all.
+	hosts := make([]*hostRoutes, 0, len(r.exactHosts)+len(r.patternHosts))
+	for _, h := range r.exactHosts {
+		hosts = append(hosts, h)
+	}
+	// Sorted by pattern, for a deterministic report.
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].pattern < hosts[j].pattern })
+	hosts = append(hosts, r.patternHosts...)
+	for _, h := range hosts {
+		for _, e := range entries {
+			if e.host != "" || shadowed[e] || len(e.samples) == 0 {
+				continue
+			}
+			for _, m := range e.route.methods {
+				samples, lost := e.resolve(&h.routeTable, r.methods.index(m), e.samples)
+				if lost && genericPattern(samples[0].Winner.path) != genericPattern(e.route.path) {
+					add(RouteIssue{Kind: IssueShadowed, Host: h.pattern, Path: e.route.path, Route: e.owner,
+						Samples: samples, Reason: "by host " + h.pattern}, m)
+				}
+			}
+		}
+	}
+
+	// Overlapping dynamic routes of a method.
+	for i, e := range entries {
+		if shadowed[e] || isStaticPath(e.route.path) || len(e.samples) == 0 {
+			continue
+		}
+		for _, f := range entries[i+1:] {
+			// Routes of one path and table are variants, see Router.When.
+			if f.owner == e.owner || f.table != e.table || f.route.path == e.route.path || shadowed[f] ||
+				isStaticPath(f.route.path) || len(f.samples) == 0 {
+				continue
+			}
+			for _, m := range e.route.methods {
+				if !containsString(f.route.methods, m) {
+					continue
+				}
+				if samples := overlap(e, f, r.methods.index(m)); len(samples) > 0 {
+					add(RouteIssue{Kind: IssueAmbiguous, Host: e.host, Path: e.route.path, Route: e.owner,
+						Other: f.owner, Samples: samples}, m)
+				}
+			}
+		}
+	}
+
+	// Routes of mounted routers.
+	for _, e := range entries {
+		sub := e.owner.mounted
+		if sub == nil || len(e.owner.expansions) > 0 && e.route != e.owner.expansions[0] {
+			continue
+		}
+		sub = sub.current()
+		subPrefix := strings.TrimRight(e.owner.MountPrefix(), "/")
+		issues = append(issues, sub.analyze(prefix+subPrefix)...)
+		issues = append(issues, r.analyzeMount(sub, e, prefix, subPrefix, owners)...)
+	}
+	return issues
+}
+
+// analyzeMount reports routes of sub, mounted by the route of e, that are
+// shadowed by routes of r.
+func (r *Router) analyzeMount(sub *Router, e *analyzeEntry, prefix, subPrefix string, owners map[*Route]*Route) []RouteIssue {
+	var issues []RouteIssue
+	for _, route := range sub.routeList {
+		stored := []*Route{route}
+		if len(route.expansions) > 0 {
+			stored = route.expansions
+		}
+		for _, s := range stored {
+			if s.host != "" {
+				continue
+			}
+			samples := samplePaths(s.path)
+			if len(samples) == 0 {
+				continue
+			}
+			var methods []string
+			var found []RouteSample
+			for _, m := range s.methods {
+				var lost []RouteSample
+				for _, p := range samples {
+					full := subPrefix + p
+					if p == "/" && subPrefix != "" {
+						full = subPrefix
+					}
+					winner := served(e.table, r.methods.index(m), full, nil)
+					owner := owners[winner]
+					if owner == e.owner || winner == nil {
+						lost = nil
+						break
+					}
+					if owner != nil {
+						winner = owner
+					}
+					lost = append(lost, RouteSample{Path: prefix + full, Winner: winner})
+				}
+				if len(lost) > 0 {
+					methods = append(methods, m)
+					found = lost
+				}
+			}
+			if len(methods) > 0 {
+				issues = append(issues, RouteIssue{Kind: IssueShadowed, Methods: methods,
+					Path: prefix + subPrefix + s.path, Route: route, Samples: found,
+					Reason: "by the parent router"})
+			}
+		}
+	}
+	return issues
+}
+
+// resolve looks up samples in table for the method at idx. lost reports
+// that no sample is served by e; samples then holds the winners.
+func (e *analyzeEntry) resolve(table *routeTable, idx int, paths []string) (samples []RouteSample, lost bool) {
+	for _, p := range paths {
+		winner := served(table, idx, p, e.route)
+		if winner == e.route {
+			return nil, false
+		}
+		if winner == nil {
+			return nil, false
+		}
+		samples = append(samples, RouteSample{Path: p, Winner: winner})
+	}
+	return samples, true
+}
+
+// served returns the route table dispatches p to for the method at idx.
+// Of the routes of a variant slot, want is returned if it is one of them,
+// else the primary one.
+func served(table *routeTable, idx int, p string, want *Route) *Route {
+	var ps Params
+	winner := table.match(idx, p, &ps)
+	if winner == nil || winner.variants == nil {
+		return winner
+	}
+	for _, v := range winner.variants {
+		if v == want {
+			return v
+		}
+	}
+	return winner.primary()
+}
+
+// overlap returns the sample paths of e and f that both patterns match,
+// with the route the table dispatches them to.
+func overlap(e, f *analyzeEntry, idx int) []RouteSample {
+	var samples []RouteSample
+	check := func(a, b *analyzeEntry) {
+		for _, p := range a.samples {
+			var ps Params
+			if _, ok := b.alone().lookup(p, &ps); !ok {
+				continue
+			}
+			samples = append(samples, RouteSample{Path: p, Winner: served(e.table, idx, p, nil)})
+		}
+	}
+	check(e, f)
+	check(f, e)
+	return samples
+}
+
+// alone returns a tree holding e's route only.
+func (e *analyzeEntry) alone() *radixTree {
+	if e.tree == nil {
+		e.tree = newRadixTree()
+		e.tree.insert(e.route.path, e.route)
+	}
+	return e.tree
+}
+
+// sameIssue reports whether a and b are the same finding, methods aside.
+func sameIssue(a, b RouteIssue) bool {
+	if a.Kind != b.Kind || a.Host != b.Host || a.Path != b.Path || a.Route != b.Route ||
+		a.Other != b.Other || a.Reason != b.Reason || len(a.Samples) != len(b.Samples) {
+		return false
+	}
+	for i := range a.Samples {
+		if a.Samples[i] != b.Samples[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// genericPattern renames the params of a tree pattern to "p", so patterns
+// differing only in param names compare equal.
+func genericPattern(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case ':':
+			b.WriteString(":p")
+			i = paramNameEnd(p, i+1) - 1
+		case '*':
+			b.WriteString("*p")
+			i = len(p)
+		case '{':
+			end := closingBrace(p, i)
+			_, pattern := splitParamDef(p[i+1 : end])
+			b.WriteString("{p:" + pattern + "}")
+			i = end
+		default:
+			b.WriteByte(p[i])
+		}
+	}
+	return b.String()
+}
+
+// samplePaths returns request paths matching the table path p: one with
+// the first sample value of every param, plus one per param with an
+// alternative value. It returns nil if a constraint admits no value.
+func samplePaths(p string) []string {
+	type part struct {
+		lit    string
+		values []string
+	}
+	var parts []part
+	for i := 0; i < len(p); {
+		switch p[i] {
+		case ':':
+			end := paramNameEnd(p, i+1)
+			parts = append(parts, part{values: []string{"x", "1"}})
+			i = end
+		case '*':
+			parts = append(parts, part{values: []string{"x", "x/y"}})
+			i = len(p)
+		case '{':
+			end := closingBrace(p, i)
+			_, pattern := splitParamDef(p[i+1 : end])
+			i = end + 1
+			if pattern == "" {
+				parts = append(parts, part{values: []string{"x", "1"}})
+				continue
+			}
+			v, ok := regexSample(pattern)
+			if !ok {
+				return nil
+			}
+			parts = append(parts, part{values: []string{v}})
+		default:
+			end := i + 1
+			for end < len(p) && strings.IndexByte(":*{", p[end]) < 0 {
+				end++
+			}
+			parts = append(parts, part{lit: p[i:end]})
+			i = end
+		}
+	}
+
+	build := func(alt int) string {
+		var b strings.Builder
+		for i, pt := range parts {
+			switch {
+			case pt.values == nil:
+				b.WriteString(pt.lit)
+			case i == alt:
+				b.WriteString(pt.values[1])
+			default:
+				b.WriteString(pt.values[0])
+			}
+		}
+		return b.String()
+	}
+	samples := []string{build(-1)}
+	for i, pt := range parts {
+		if len(pt.values) > 1 {
+			samples = append(samples, build(i))
+		}
+	}
+	return samples
+}
+
+// regexSample returns a short non-empty string without '/' matching the
+// whole of the param constraint pattern.
+func regexSample(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	if !writeRegexSample(&b, re.Simplify()) {
+		return "", false
+	}
+	v := b.String()
+	if v == "" || strings.IndexByte(v, '/') >= 0 {
+		return "", false
+	}
+	if whole, err := regexp.Compile("^(?:" + pattern + ")$"); err != nil || !whole.MatchString(v) {
+		return "", false
+	}
+	return v, true
+}
+
+// writeRegexSample writes a string matched by re. Repetitions are taken
+// once, so values are not empty when they can avoid it.
+func writeRegexSample(b *strings.Builder, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return false
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			if lo == '/' {
+				lo++
+			}
+			if lo <= hi {
+				b.WriteRune(lo)
+				return true
+			}
+		}
+		return false
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte('x')
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest:
+		return writeRegexSample(b, re.Sub[0])
+	case syntax.OpRepeat:
+		n := max(re.Min, 1)
+		if re.Max == 0 {
+			n = 0
+		}
+		for i := 0; i < n; i++ {
+			if !writeRegexSample(b, re.Sub[0]) {
+				return false
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !writeRegexSample(b, sub) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			var alt strings.Builder
+			if writeRegexSample(&alt, sub) {
+				b.WriteString(alt.String())
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+	"github.com/gookit/goutil/x/ccolor"
+)
+
+func TestRouter_Analyze_Clean(t *testing.T) {
+	r := New()
+	r.GET("/users", textHandler("list"))
+	r.GET("/users/new", textHandler("new"))
+	r.GET("/users/{id}", textHandler("show"))
+	r.POST("/users/{id}", textHandler("update"))
+	r.GET("/posts[/{page:\\d+}]", textHandler("posts"))
+
+	a := r.Analyze()
+	assert.True(t, a.OK(), a.String())
+	assert.Nil(t, a.Err())
+}
+
+func TestRouter_Analyze_AmbiguousAndShadowed(t *testing.T) {
+	r := New()
+	show := r.GET("/users/{id}", textHandler("show"))
+	all := r.GET("/users/{all:.*}", textHandler("all"))
+	broad := r.GET(`/p/{key:[0-9a-z]+}`, textHandler("key"))
+	narrow := r.GET(`/p/{n:\d+}`, textHandler("num"))
+
+	a := r.Analyze()
+	assert.False(t, a.OK())
+
+	ambiguous := a.Filter(IssueAmbiguous)
+	assert.Len(t, ambiguous, 1)
+	assert.Same(t, show, ambiguous[0].Route)
+	assert.Same(t, all, ambiguous[0].Other)
+	assert.Eq(t, []string{GET}, ambiguous[0].Methods)
+	assert.Eq(t, "/users/x", ambiguous[0].Samples[0].Path)
+	assert.Same(t, show, ambiguous[0].Samples[0].Winner)
+
+	shadowed := a.Filter(IssueShadowed)
+	assert.Len(t, shadowed, 1)
+	assert.Same(t, narrow, shadowed[0].Route)
+	assert.Eq(t, "/p/0", shadowed[0].Samples[0].Path)
+	assert.Same(t, broad, shadowed[0].Samples[0].Winner)
+
+	err := a.Err(IssueShadowed, IssueUnreachable)
+	assert.Err(t, err)
+	assert.StrContains(t, err.Error(), `shadowed: GET /p/{n:\d+}: /p/0 -> /p/{key:[0-9a-z]+}`)
+}
+
+func TestRouter_Analyze_OptionalExpansions(t *testing.T) {
+	r := New()
+	r.GET("/items/{id:[a-z0-9]+}", textHandler("item"))
+	opt := r.GET(`/items[/{num:\d+}]`, textHandler("opt"))
+
+	shadowed := r.Analyze().Filter(IssueShadowed)
+	assert.Len(t, shadowed, 1)
+	assert.Same(t, opt, shadowed[0].Route)
+	assert.Eq(t, `/items/{num:\d+}`, shadowed[0].Path)
+}
+
+func TestRouter_Analyze_Unreachable(t *testing.T) {
+	r := New()
+	r.Add(`/a/{id:x/y}`, textHandler("slash"), GET, POST)
+	a := r.Analyze()
+	assert.Len(t, a.Issues, 1)
+	assert.Eq(t, IssueUnreachable, a.Issues[0].Kind)
+	assert.Eq(t, []string{GET, POST}, a.Issues[0].Methods)
+
+	r2 := New(InterceptAll("/maintenance"))
+	r2.GET("/maintenance", textHandler("down"))
+	home := r2.GET("/", textHandler("home"))
+	a = r2.Analyze()
+	assert.Len(t, a.Issues, 1)
+	assert.Same(t, home, a.Issues[0].Route)
+	assert.Eq(t, "InterceptAll /maintenance", a.Issues[0].Reason)
+}
+
+func TestRouter_Analyze_Hosts(t *testing.T) {
+	r := New()
+	r.Host("api.example.com", func() {
+		r.GET("/{all:.*}", textHandler("api"))
+		r.GET("/status", textHandler("api status"))
+	})
+	r.GET("/status", textHandler("status"))
+	docs := r.GET("/docs/{page}", textHandler("docs"))
+
+	// Overridden on the host with the same pattern is not an issue.
+	a := r.Analyze()
+	assert.Len(t, a.Issues, 1, a.String())
+	assert.Eq(t, IssueShadowed, a.Issues[0].Kind)
+	assert.Same(t, docs, a.Issues[0].Route)
+	assert.Eq(t, "api.example.com", a.Issues[0].Host)
+	assert.Eq(t, "/*all", a.Issues[0].Samples[0].Winner.Path())
+}
+
+func TestRouter_Analyze_Mounts(t *testing.T) {
+	sub := New()
+	sub.GET("/stats", textHandler("sub stats"))
+	subList := sub.GET("/list", textHandler("sub list"))
+	sub.GET("/{a}", textHandler("a"))
+	sub.GET("/{b:.+}", textHandler("b"))
+
+	r := New()
+	parentList := r.GET("/admin/list", textHandler("parent list"))
+	r.Mount("/admin", sub)
+
+	a := r.Analyze()
+	assert.Len(t, a.Issues, 2, a.String())
+
+	assert.Eq(t, IssueAmbiguous, a.Issues[0].Kind)
+	assert.Eq(t, "/admin/:a", a.Issues[0].Path)
+	assert.Eq(t, "/admin/x", a.Issues[0].Samples[0].Path)
+
+	assert.Eq(t, IssueShadowed, a.Issues[1].Kind)
+	assert.Same(t, subList, a.Issues[1].Route)
+	assert.Eq(t, "/admin/list", a.Issues[1].Path)
+	assert.Same(t, parentList, a.Issues[1].Samples[0].Winner)
+	assert.Eq(t, "by the parent router", a.Issues[1].Reason)
+}
+
+func TestRouter_Freeze_DebugWarnsIssues(t *testing.T) {
+	var buf bytes.Buffer
+	ccolor.SetOutput(&buf)
+	defer ccolor.SetOutput(os.Stdout)
+
+	r := New()
+	r.GET("/users/{id}", textHandler("show"))
+	r.GET("/users/{all:.*}", textHandler("all"))
+	r.Freeze()
+	assert.Eq(t, "", buf.String())
+
+	Debug(true)
+	defer Debug(false)
+	buf.Reset()
+	r = New()
+	r.GET("/users/{id}", textHandler("show"))
+	r.GET("/users/{all:.*}", textHandler("all"))
+	r.Freeze()
+	assert.StrContains(t, buf.String(), "rux: route "+r.Analyze().Issues[0].String())
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// AutoRoute registers the handler methods of controller by name. See
+// RouteGroup.AutoRoute.
+func (r *Router) AutoRoute(basePath string, controller any, middles ...HandlerFunc) []*Route {
+	return r.group().AutoRoute(basePath, controller, middles...)
+}
+
+// AutoRoute registers the exported func(*Context) methods of the
+// controller struct in a nested group, mapping method names to methods
+// and paths:
+//
+//	GetUsers(c)               GET    /users
+//	PostUser(c)               POST   /user
+//	GetUserByID(c)            GET    /user/{id}
+//	PutUserRoleByIDAndRole(c) PUT    /user-role/{id}/{role}
+//	DeleteByID(c)             DELETE /{id}
+//
+// A name is <Verb><Path>[By<Param>[And<Param>...]], Verb one of Get, Post,
+// Put, Patch and Delete. Path is kebab-cased and params snake-cased. Route
+// names are the lower-cased controller type name and the snake-cased
+// method name: users_get_user_by_id.
+//
+// Other methods are mapped by a Routes() map[string]string method, or by
+// route tags on blank fields, from method name to "METHODS /path", or to
+// "-" to skip the method. Routes() takes precedence:
+//
+//	type Users struct {
+//		_ struct{} `route:"Search GET,POST /search"`
+//		_ struct{} `route:"Helper -"`
+//	}
+//
+// AutoRoute panics, listing them, if methods cannot be mapped. As for
+// Resource, a Uses() map[string][]HandlerFunc method sets per-method
+// middlewares. Routes are registered and returned sorted by method name.
+func (g *RouteGroup) AutoRoute(basePath string, controller any, middles ...HandlerFunc) []*Route {
+	cv := reflect.ValueOf(controller)
+	if cv.Kind() != reflect.Ptr || cv.Elem().Kind() != reflect.Struct {
+		panic("rux: AutoRoute controller must be a pointer to struct")
+	}
+	typeName := cv.Elem().Type().Name()
+	overrides := autoRouteOverrides(cv)
+	perActionMW := usesOf(cv)
+
+	var mapped []autoRoute
+	var unmapped []string
+	ct := cv.Type()
+	for i := 0; i < ct.NumMethod(); i++ {
+		name := ct.Method(i).Name
+		handler, ok := cv.Method(i).Interface().(func(*Context))
+		if !ok {
+			continue
+		}
+		ar, ok := overrides[name]
+		if ok {
+			delete(overrides, name)
+		} else if ar, ok = conventionRoute(name); !ok {
+			unmapped = append(unmapped, name)
+			continue
+		}
+		if !ar.skip {
+			ar.action, ar.handler = name, handler
+			mapped = append(mapped, ar)
+		}
+	}
+	if len(overrides) > 0 {
+		names := make([]string, 0, len(overrides))
+		for name := range overrides {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		panic(fmt.Sprintf("rux: AutoRoute %s has no handler methods %s", typeName, strings.Join(names, ", ")))
+	}
+	if len(unmapped) > 0 {
+		panic(fmt.Sprintf("rux: AutoRoute cannot map methods of %s: %s; name them <Verb><Path>[By<Param>] or map them in Routes()",
+			typeName, strings.Join(unmapped, ", ")))
+	}
+
+	grp := g.Group(basePath, middles...)
+	prefix := strings.ToLower(typeName) + "_"
+	routes := make([]*Route, 0, len(mapped))
+	for _, ar := range mapped {
+		route := grp.AddNamed(prefix+snakeCase(ar.action), ar.path, ar.handler, ar.methods...)
+		route.setHandlerName(methodName(ct, ar.action))
+		if mws, ok := perActionMW[ar.action]; ok {
+			route.Use(mws...)
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// autoRoute is the route of a controller method of AutoRoute.
+type autoRoute struct {
+	action  string
+	handler HandlerFunc
+	methods []string
+	path    string
+	// skip is set for methods mapped to "-".
+	skip bool
+}
+
+// conventionRoute maps a method name <Verb><Path>[By<Param>[And<Param>]]
+// to its route.
+func conventionRoute(name string) (autoRoute, bool) {
+	verb, rest := cutVerb(name)
+	if verb == "" {
+		return autoRoute{}, false
+	}
+	words := camelWords(rest)
+	by := len(words)
+	for i, w := range words {
+		if w == "By" {
+			by = i
+			break
+		}
+	}
+
+	var path string
+	if by > 0 {
+		path = "/" + strings.ToLower(strings.Join(words[:by], "-"))
+	}
+	if by < len(words) {
+		// Params are separated by And; none may be empty.
+		params, start := words[by+1:], 0
+		for i := 0; i <= len(params); i++ {
+			if i < len(params) && params[i] != "And" {
+				continue
+			}
+			if i == start {
+				return autoRoute{}, false
+			}
+			path += "/{" + strings.ToLower(strings.Join(params[start:i], "_")) + "}"
+			start = i + 1
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	return autoRoute{methods: []string{verb}, path: path}, true
+}
+
+// autoRouteOverrides returns the routes mapped by the route tags and the
+// Routes() method of the controller cv.
+func autoRouteOverrides(cv reflect.Value) map[string]autoRoute {
+	typeName := cv.Elem().Type().Name()
+	specs := make(map[string]string)
+	st := cv.Elem().Type()
+	for i := 0; i < st.NumField(); i++ {
+		tag, ok := st.Field(i).Tag.Lookup("route")
+		if !ok {
+			continue
+		}
+		name, spec, _ := strings.Cut(strings.TrimSpace(tag), " ")
+		specs[name] = spec
+	}
+	if m := cv.MethodByName("Routes"); m.IsValid() {
+		if routes, ok := m.Interface().(func() map[string]string); ok {
+			for name, spec := range routes() {
+				specs[name] = spec
+			}
+		}
+	}
+
+	overrides := make(map[string]autoRoute, len(specs))
+	for name, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "-" {
+			overrides[name] = autoRoute{skip: true}
+			continue
+		}
+		methods, path, ok := strings.Cut(spec, " ")
+		path = strings.TrimSpace(path)
+		if !ok || path == "" || name == "" {
+			panic(fmt.Sprintf("rux: AutoRoute %s: invalid route %q for %s, want \"METHODS /path\" or \"-\"",
+				typeName, spec, name))
+		}
+		overrides[name] = autoRoute{methods: strings.Split(strings.ToUpper(methods), ","), path: path}
+	}
+	return overrides
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+type autoUsers struct {
+	_ struct{} `route:"Search GET,POST /search"`
+	_ struct{} `route:"Helper -"`
+}
+
+func (*autoUsers) GetUsers(c *Context)               { c.Text(200, "list") }
+func (*autoUsers) PostUser(c *Context)               { c.Text(200, "create") }
+func (*autoUsers) GetUserByID(c *Context)            { c.Text(200, "user "+c.Param("id")) }
+func (*autoUsers) PutUserRoleByIDAndRole(c *Context) { c.Text(200, c.Param("id")+" "+c.Param("role")) }
+func (*autoUsers) GetPostsByUserID(c *Context)       { c.Text(200, "posts of "+c.Param("user_id")) }
+func (*autoUsers) DeleteByID(c *Context)             { c.Text(200, "delete "+c.Param("id")) }
+func (*autoUsers) Search(c *Context)                 { c.Text(200, "search") }
+func (*autoUsers) Helper(c *Context)                 {}
+func (*autoUsers) Archive(c *Context)                { c.Text(200, "archive "+c.Param("id")) }
+
+// Routes overrides the tags and maps Archive.
+func (*autoUsers) Routes() map[string]string {
+	return map[string]string{"Archive": "post /{id}/archive"}
+}
+
+func (*autoUsers) Uses() map[string][]HandlerFunc {
+	return map[string][]HandlerFunc{
+		"GetUsers": {func(c *Context) { c.Resp.Header().Set("X-Mw", "on"); c.Next() }},
+	}
+}
+
+func TestRouter_AutoRoute(t *testing.T) {
+	r := New()
+	routes := r.AutoRoute("/api", &autoUsers{})
+
+	var names []string
+	for _, route := range routes {
+		names = append(names, route.Name())
+	}
+	assert.Eq(t, []string{
+		"autousers_archive", "autousers_delete_by_id", "autousers_get_posts_by_user_id",
+		"autousers_get_user_by_id", "autousers_get_users", "autousers_post_user",
+		"autousers_put_user_role_by_id_and_role", "autousers_search",
+	}, names)
+
+	w := serve(r, GET, "/api/users")
+	assert.Eq(t, "list", w.Body.String())
+	assert.Eq(t, "on", w.Header().Get("X-Mw"))
+	assert.Eq(t, "create", serve(r, POST, "/api/user").Body.String())
+	assert.Eq(t, "user 7", serve(r, GET, "/api/user/7").Body.String())
+	assert.Eq(t, "7 admin", serve(r, PUT, "/api/user-role/7/admin").Body.String())
+	assert.Eq(t, "posts of 3", serve(r, GET, "/api/posts/3").Body.String())
+	assert.Eq(t, "delete 9", serve(r, DELETE, "/api/9").Body.String())
+	assert.Eq(t, "search", serve(r, POST, "/api/search").Body.String())
+	assert.Eq(t, "archive 5", serve(r, POST, "/api/5/archive").Body.String())
+}
+
+type autoBad struct{}
+
+func (*autoBad) GetUsers(c *Context)   {}
+func (*autoBad) Search(c *Context)     {}
+func (*autoBad) GetByIDAnd(c *Context) {}
+
+// Count is not a handler and is ignored.
+func (*autoBad) Count() int { return 0 }
+
+type autoMissing struct {
+	_ struct{} `route:"Find GET /find"`
+}
+
+func (*autoMissing) GetUsers(c *Context) {}
+
+type autoInvalid struct {
+	_ struct{} `route:"GetUsers /users"`
+}
+
+func (*autoInvalid) GetUsers(c *Context) {}
+
+func TestRouter_AutoRoute_Panics(t *testing.T) {
+	r := New()
+	assert.PanicsMsg(t, func() { r.AutoRoute("/", &autoBad{}) },
+		"rux: AutoRoute cannot map methods of autoBad: GetByIDAnd, Search; name them <Verb><Path>[By<Param>] or map them in Routes()")
+	assert.PanicsMsg(t, func() { r.AutoRoute("/", &autoMissing{}) },
+		"rux: AutoRoute autoMissing has no handler methods Find")
+	assert.PanicsMsg(t, func() { r.AutoRoute("/", &autoInvalid{}) },
+		`rux: AutoRoute autoInvalid: invalid route "/users" for GetUsers, want "METHODS /path" or "-"`)
+	assert.PanicsMsg(t, func() { r.AutoRoute("/", autoBad{}) },
+		"rux: AutoRoute controller must be a pointer to struct")
+}
+
+func TestConventionRoute(t *testing.T) {
+	tests := map[string]string{
+		"Get":                 "GET /",
+		"GetUsers":            "GET /users",
+		"GetHTMLExport":       "GET /html-export",
+		"PatchUserByUserID":   "PATCH /user/{user_id}",
+		"GetFileByDirAndName": "GET /file/{dir}/{name}",
+		"DeleteByID":          "DELETE /{id}",
+	}
+	for name, want := range tests {
+		ar, ok := conventionRoute(name)
+		assert.True(t, ok, name)
+		assert.Eq(t, want, ar.methods[0]+" "+ar.path)
+	}
+	for _, name := range []string{"Index", "Getter", "GetUserBy", "GetByAndID", "GetByIDAnd"} {
+		_, ok := conventionRoute(name)
+		assert.False(t, ok, name)
+	}
+}
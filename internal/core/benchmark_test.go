@@ -43,6 +43,12 @@ func BenchmarkV2_Param5(b *testing.B) {
 	runReq(b, r, "GET", "/a/1/b/2/c/3/d/4/e/5")
 }
 
+func BenchmarkV2_MixedSegment(b *testing.B) {
+	r := New()
+	r.GET("/files/{name}.{ext}", noopHandler)
+	runReq(b, r, "GET", "/files/archive.tar.gz")
+}
+
 func BenchmarkV2_Wildcard(b *testing.B) {
 	r := New()
 	r.GET("/files/*path", noopHandler)
@@ -0,0 +1,333 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Condition is a request predicate a route is dispatched by, in addition
+// to its method and path. Routes registered through Router.When or
+// RouteGroup.When carry conditions; several of them may share a method
+// and path, see Router.When for the evaluation order.
+type Condition struct {
+	desc   string
+	status int
+	fn     func(*http.Request) bool
+	// id tells apart MatchFunc conditions, whose desc does not determine
+	// fn. It is 0 for the others.
+	id uint64
+}
+
+// String returns the condition as shown in route dumps and RouteInfo.
+func (c Condition) String() string { return c.desc }
+
+// Status returns the response status when the condition rejects every
+// route of a path, see Router.When.
+func (c Condition) Status() int { return c.status }
+
+// WithStatus returns a copy of c rejecting requests with status.
+func (c Condition) WithStatus(status int) Condition {
+	c.status = status
+	return c
+}
+
+// Match reports whether req satisfies the condition.
+func (c Condition) Match(req *http.Request) bool { return c.fn(req) }
+
+// HeaderIs matches requests with a name header equal to value. A failed
+// match maps to 406 Not Acceptable.
+//
+//	r.When(rux.HeaderIs("Accept-Version", "2")).GET("/users", listUsersV2)
+func HeaderIs(name, value string) Condition {
+	name = http.CanonicalHeaderKey(name)
+	return Condition{
+		desc:   "header " + name + ": " + value,
+		status: http.StatusNotAcceptable,
+		fn: func(req *http.Request) bool {
+			for _, v := range req.Header[name] {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// HeaderMatches matches requests with a name header matching the regex
+// pattern. It panics if pattern does not compile. A failed match maps to
+// 406 Not Acceptable.
+func HeaderMatches(name, pattern string) Condition {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("rux: invalid header pattern %q: %v", pattern, err))
+	}
+	name = http.CanonicalHeaderKey(name)
+	return Condition{
+		desc:   "header " + name + " ~ " + pattern,
+		status: http.StatusNotAcceptable,
+		fn: func(req *http.Request) bool {
+			for _, v := range req.Header[name] {
+				if re.MatchString(v) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// HasQuery matches requests with a name query param, of any value. A
+// failed match maps to 406 Not Acceptable.
+func HasQuery(name string) Condition {
+	return Condition{
+		desc:   "query " + name,
+		status: http.StatusNotAcceptable,
+		fn: func(req *http.Request) bool {
+			return req.URL.Query().Has(name)
+		},
+	}
+}
+
+// QueryIs matches requests whose name query param equals value, e.g.
+// ?format=csv. A failed match maps to 406 Not Acceptable.
+func QueryIs(name, value string) Condition {
+	return Condition{
+		desc:   "query " + name + "=" + value,
+		status: http.StatusNotAcceptable,
+		fn: func(req *http.Request) bool {
+			for _, v := range req.URL.Query()[name] {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// ContentTypeIs matches requests whose Content-Type media type, params
+// ignored, is one of types. A type may end with "/*" to match any
+// subtype. A failed match maps to 415 Unsupported Media Type.
+//
+//	r.When(rux.ContentTypeIs("application/json")).POST("/import", importJSON)
+func ContentTypeIs(types ...string) Condition {
+	if len(types) == 0 {
+		panic("rux: ContentTypeIs needs at least one media type")
+	}
+	types = append([]string{}, types...)
+	for i, t := range types {
+		types[i] = strings.ToLower(strings.TrimSpace(t))
+	}
+	return Condition{
+		desc:   "content-type " + strings.Join(types, " | "),
+		status: http.StatusUnsupportedMediaType,
+		fn: func(req *http.Request) bool {
+			mt, _, _ := strings.Cut(req.Header.Get(ContentType), ";")
+			mt = strings.ToLower(strings.TrimSpace(mt))
+			if mt == "" {
+				return false
+			}
+			for _, t := range types {
+				if t == mt || strings.HasSuffix(t, "/*") && strings.HasPrefix(mt, t[:len(t)-1]) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// MatchFunc matches requests for which fn returns true. desc names the
+// condition in route dumps. A failed match maps to 404 Not Found, which
+// WithStatus can change.
+//
+// Each call returns a distinct condition, whatever desc: routes sharing
+// a MatchFunc condition, as weighted variants, must reuse the value it
+// returned.
+func MatchFunc(desc string, fn func(*http.Request) bool) Condition {
+	if fn == nil {
+		panic("rux: MatchFunc func cannot be nil")
+	}
+	return Condition{desc: desc, status: http.StatusNotFound, fn: fn, id: matchFuncIDs.Add(1)}
+}
+
+// matchFuncIDs numbers the conditions of MatchFunc.
+var matchFuncIDs atomic.Uint64
+
+// When returns a group for routes dispatched only to requests satisfying
+// all of conds. See RouteGroup.When.
+func (r *Router) When(conds ...Condition) *RouteGroup {
+	return r.group().When(conds...)
+}
+
+// When returns a nested group with the same prefix whose routes are only
+// dispatched to requests satisfying all of conds, on top of the
+// conditions inherited from g.
+//
+// Routes with conditions may share a method and path with each other and
+// with at most one route without conditions, in any registration order.
+// Routes with the same conditions must be weighted variants, see Variant.
+// A request is dispatched to the first route whose conditions all hold:
+// routes with more conditions are tried first, then in registration
+// order, and the route without conditions comes last as the default.
+//
+// When no route matches, the response status is the one of the failed
+// condition of the closest route (the one with the most conditions
+// passed, the first tried on a tie): 406 for header and query conditions, 415 for ContentTypeIs,
+// or the WithStatus of a condition. 406 and 415 fail the request with an
+// HTTPError, rendered by the error hook; 404 runs the NotFound handlers.
+//
+//	r.GET("/users", listUsers) // default
+//	v2 := r.When(rux.HeaderIs("Accept-Version", "2"))
+//	v2.GET("/users", listUsersV2)
+//	r.When(rux.QueryIs("format", "csv")).GET("/users", exportUsers)
+func (g *RouteGroup) When(conds ...Condition) *RouteGroup {
+	ng := g.Group("")
+	ng.conds = append(append([]Condition{}, g.conds...), conds...)
+	return ng
+}
+
+// Conditions returns the conditions of the route, or nil.
+func (r *Route) Conditions() []Condition { return r.conds }
+
+// condStrings returns the descriptions of conds.
+func condStrings(conds []Condition) []string {
+	if len(conds) == 0 {
+		return nil
+	}
+	out := make([]string, len(conds))
+	for i, c := range conds {
+		out[i] = c.desc
+	}
+	return out
+}
+
+// addVariant stores route on the method and path slot old holds, nil if
+// empty. A route with conditions or a variant (see Router.Variant) is
+// stored in a slot route, whose variants are the routes sharing the slot
+// in evaluation order, those with the same conditions next to each
+// other. ok is false for a duplicate: two routes with the same conditions
+// that are neither weighted variants.
+func addVariant(old, route *Route) (slot *Route, ok bool) {
+	switch {
+	case old == nil:
+		if len(route.conds) == 0 && route.variant == nil {
+			return route, true
+		}
+		return &Route{path: route.path, methods: route.methods, host: route.host, variants: []*Route{route}}, true
+	case old.variants == nil:
+		slot = &Route{path: old.path, methods: old.methods, host: old.host, variants: []*Route{old}}
+	default:
+		slot = old
+	}
+
+	// Routes with the same conditions pick a weighted variant.
+	start, end := -1, 0
+	for i, v := range slot.variants {
+		if sameConds(v.conds, route.conds) {
+			if start < 0 {
+				start = i
+			}
+			end = i + 1
+		}
+	}
+	i := end
+	if start >= 0 {
+		for _, v := range slot.variants[start:end] {
+			if v.Weight() == 0 && route.Weight() == 0 {
+				return old, false
+			}
+		}
+		checkWeights(slot.variants[start:end], route)
+	} else {
+		// Keep more conditions first, then registration order.
+		i = 0
+		for i < len(slot.variants) && len(slot.variants[i].conds) >= len(route.conds) {
+			i++
+		}
+	}
+	slot.variants = append(slot.variants, nil)
+	copy(slot.variants[i+1:], slot.variants[i:])
+	slot.variants[i] = route
+	return slot, true
+}
+
+// cloneSlot returns a copy of the slot route of addVariant, so adding
+// variants to the copy leaves route unchanged. Other routes are returned
+// as is.
+func cloneSlot(route *Route) *Route {
+	if route == nil || route.variants == nil {
+		return route
+	}
+	c := *route
+	c.variants = slices.Clone(route.variants)
+	return &c
+}
+
+// sameConds reports whether a and b are the same conditions. Built-in
+// conditions are compared by description, which follows from their
+// arguments, MatchFunc ones by identity.
+func sameConds(a, b []Condition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].id != b[i].id || a[i].desc != b[i].desc {
+			return false
+		}
+	}
+	return true
+}
+
+// pick returns the first variant of the slot r whose conditions all hold
+// for req, picking by weight among variants with the same conditions. On
+// a miss it returns the closest variant and the status of the condition
+// it failed on.
+func (r *Route) pick(req *http.Request) (match, closest *Route, status int) {
+	best := -1
+	for i := 0; i < len(r.variants); {
+		v := r.variants[i]
+		j := i + 1
+		for j < len(r.variants) && sameConds(r.variants[j].conds, v.conds) {
+			j++
+		}
+		passed, failed := v.check(req)
+		if failed == nil {
+			if j-i == 1 {
+				return v, nil, 0
+			}
+			return pickWeighted(r.variants[i:j], req), nil, 0
+		}
+		if passed > best {
+			best, closest, status = passed, v, failed.status
+		}
+		i = j
+	}
+	return nil, closest, status
+}
+
+// check evaluates the conditions of r in order. It returns the number of
+// conditions passed and the failed one, nil if all hold.
+func (r *Route) check(req *http.Request) (int, *Condition) {
+	for i := range r.conds {
+		if !r.conds[i].fn(req) {
+			return i, &r.conds[i]
+		}
+	}
+	return len(r.conds), nil
+}
+
+// primary returns the route a variant slot resolves to without a request:
+// its last variant, the default route if it has one. Other routes return
+// themselves.
+func (r *Route) primary() *Route {
+	if r.variants == nil {
+		return r
+	}
+	return r.variants[len(r.variants)-1]
+}
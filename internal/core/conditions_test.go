@@ -0,0 +1,181 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestCondition_Match(t *testing.T) {
+	req := httptest.NewRequest(GET, "/users?format=csv&debug", nil)
+	req.Header.Set("Accept-Version", "2")
+	req.Header.Set(ContentType, "Application/JSON; charset=utf-8")
+
+	assert.True(t, HeaderIs("accept-version", "2").Match(req))
+	assert.False(t, HeaderIs("Accept-Version", "1").Match(req))
+	assert.True(t, HeaderMatches("Accept-Version", `^[23]$`).Match(req))
+	assert.False(t, HeaderMatches("X-Missing", `.*`).Match(req))
+	assert.True(t, HasQuery("debug").Match(req))
+	assert.False(t, HasQuery("page").Match(req))
+	assert.True(t, QueryIs("format", "csv").Match(req))
+	assert.False(t, QueryIs("format", "json").Match(req))
+	assert.True(t, ContentTypeIs("text/plain", "application/json").Match(req))
+	assert.True(t, ContentTypeIs("application/*").Match(req))
+	assert.False(t, ContentTypeIs("text/*").Match(req))
+
+	c := MatchFunc("is GET", func(r *http.Request) bool { return r.Method == GET })
+	assert.True(t, c.Match(req))
+	assert.Eq(t, "is GET", c.String())
+	assert.Eq(t, http.StatusNotFound, c.Status())
+	assert.Eq(t, http.StatusForbidden, c.WithStatus(http.StatusForbidden).Status())
+	assert.Eq(t, http.StatusUnsupportedMediaType, ContentTypeIs("text/csv").Status())
+
+	assert.PanicsMsg(t, func() { ContentTypeIs() }, "rux: ContentTypeIs needs at least one media type")
+	assert.Panics(t, func() { HeaderMatches("X", "(") })
+}
+
+func TestRouter_When_Dispatch(t *testing.T) {
+	r := New()
+	r.GET("/users", textHandler("default"))
+	r.When(HeaderIs("Accept-Version", "2")).GET("/users", textHandler("v2"))
+	r.When(QueryIs("format", "csv")).GET("/users", textHandler("csv"))
+	r.When(HeaderIs("Accept-Version", "2"), QueryIs("format", "csv")).GET("/users", textHandler("v2 csv"))
+
+	assert.Eq(t, "default", serve(r, GET, "/users").Body.String())
+	assert.Eq(t, "v2", serve(r, GET, "/users", "Accept-Version", "2").Body.String())
+	assert.Eq(t, "csv", serve(r, GET, "/users?format=csv").Body.String())
+	// More conditions are tried first.
+	assert.Eq(t, "v2 csv", serve(r, GET, "/users?format=csv", "Accept-Version", "2").Body.String())
+	// HEAD is mirrored with the conditions.
+	assert.Eq(t, 200, serve(r, HEAD, "/users", "Accept-Version", "2").Code)
+
+	route, _, ok := r.Match(GET, "/users")
+	assert.True(t, ok)
+	assert.Empty(t, route.Conditions())
+}
+
+func TestRouter_When_Dynamic(t *testing.T) {
+	r := New()
+	api := r.NewGroup("/api")
+	api.When(HeaderIs("Accept-Version", "2")).GET("/users/{id}", func(c *Context) {
+		c.Text(200, "v2 "+c.Param("id"))
+	})
+	api.GET("/users/{id}", func(c *Context) { c.Text(200, "v1 "+c.Param("id")) })
+	r.When(HasQuery("zip")).GET("/files/*path", textHandler("zip"))
+	r.GET("/files/*path", textHandler("file"))
+
+	assert.Eq(t, "v1 7", serve(r, GET, "/api/users/7").Body.String())
+	assert.Eq(t, "v2 7", serve(r, GET, "/api/users/7", "Accept-Version", "2").Body.String())
+	assert.Eq(t, "zip", serve(r, GET, "/files/a/b?zip").Body.String())
+	assert.Eq(t, "file", serve(r, GET, "/files/a/b").Body.String())
+}
+
+func TestRouter_When_Rejected(t *testing.T) {
+	r := New()
+	v1 := r.When(HeaderIs("Accept-Version", "1"))
+	v1.GET("/items", textHandler("v1"))
+	v1.When(ContentTypeIs("application/json")).POST("/items", textHandler("json"))
+	r.When(ContentTypeIs("text/csv")).POST("/items", textHandler("csv"))
+	r.When(MatchFunc("never", func(*http.Request) bool { return false })).GET("/secret", textHandler("secret"))
+	r.When(MatchFunc("never", func(*http.Request) bool { return false }).WithStatus(403)).GET("/admin", textHandler("admin"))
+	r.NotFound(textHandler("nf"))
+
+	w := serve(r, GET, "/items", "Accept-Version", "3")
+	assert.Eq(t, http.StatusNotAcceptable, w.Code)
+	assert.Eq(t, ProblemJSON, w.Header().Get(ContentType))
+
+	assert.Eq(t, "json", serve(r, POST, "/items", "Accept-Version", "1", ContentType, "application/json").Body.String())
+	assert.Eq(t, "csv", serve(r, POST, "/items", ContentType, "text/csv").Body.String())
+	// The closest route passed its header condition.
+	assert.Eq(t, http.StatusUnsupportedMediaType, serve(r, POST, "/items", "Accept-Version", "1", ContentType, "text/xml").Code)
+	// On a tie, the route tried first.
+	assert.Eq(t, http.StatusNotAcceptable, serve(r, POST, "/items", ContentType, "text/xml").Code)
+
+	assert.Eq(t, "nf", serve(r, GET, "/secret").Body.String())
+	assert.Eq(t, http.StatusForbidden, serve(r, GET, "/admin").Code)
+}
+
+func TestRouter_When_ErrorHook(t *testing.T) {
+	r := New()
+	g := r.NewGroup("/api")
+	g.OnError(func(c *Context) {
+		c.Text(c.router.ToHTTPError(c.FirstError()).Status, "hooked")
+	})
+	g.When(HeaderIs("Accept-Version", "2")).GET("/users", textHandler("v2"))
+
+	w := serve(r, GET, "/api/users")
+	assert.Eq(t, http.StatusNotAcceptable, w.Code)
+	assert.Eq(t, "hooked", w.Body.String())
+}
+
+func TestRouter_When_Duplicates(t *testing.T) {
+	r := New()
+	r.GET("/x", textHandler("a"))
+	r.When(HasQuery("q")).GET("/x", textHandler("b"))
+	assert.PanicsMsg(t, func() { r.GET("/x", textHandler("c")) }, "rux: duplicate static route: GET /x")
+
+	r.When(HasQuery("q")).GET("/y/{id}", textHandler("a"))
+	r.GET("/y/{id}", textHandler("b"))
+	assert.PanicsMsg(t, func() { r.GET("/y/{id}", textHandler("c")) }, "rux: duplicate route registration: /y/:id")
+
+	r.When(HasQuery("q")).GET("/z/*all", textHandler("a"))
+	assert.PanicsMsg(t, func() { r.GET("/z/*rest", textHandler("b")) }, "rux: conflicting wildcard at /z/*rest")
+}
+
+// MatchFunc conditions are told apart by identity, not by description.
+func TestRouter_When_MatchFuncIdentity(t *testing.T) {
+	r := New()
+	isA := MatchFunc("custom", func(req *http.Request) bool { return req.URL.Query().Has("a") })
+	isB := MatchFunc("custom", func(req *http.Request) bool { return req.URL.Query().Has("b") })
+	r.When(isA).GET("/m", textHandler("a"))
+	r.When(isB).GET("/m", textHandler("b"))
+	assert.PanicsMsg(t, func() { r.When(isA).GET("/m", textHandler("c")) }, "rux: duplicate static route: GET /m")
+
+	assert.Eq(t, "a", serve(r, GET, "/m?a").Body.String())
+	assert.Eq(t, "b", serve(r, GET, "/m?b").Body.String())
+}
+
+func TestRouter_When_Info(t *testing.T) {
+	r := New()
+	r.When(HeaderIs("Accept-Version", "2"), QueryIs("format", "csv")).GET("/users", textHandler("v2"))
+	r.GET("/users", textHandler("v1"))
+
+	routes := r.Routes()
+	assert.Eq(t, []string{"header Accept-Version: 2", "query format=csv"}, routes[0].Conditions)
+	assert.Nil(t, routes[1].Conditions)
+	assert.True(t, strings.Contains(r.String(), "when header Accept-Version: 2, query format=csv"))
+}
+
+func TestRouter_When_ExplainAndAnalyze(t *testing.T) {
+	r := New()
+	r.GET("/users/{id}", textHandler("v1"))
+	r.When(HeaderIs("Accept-Version", "2")).GET("/users/{id}", textHandler("v2"))
+	r.When(ContentTypeIs("application/json")).POST("/users", textHandler("create"))
+
+	assert.True(t, r.Analyze().OK())
+
+	req := httptest.NewRequest(GET, "/users/1", nil)
+	req.Header.Set("Accept-Version", "2")
+	ex := r.ExplainRequest(req)
+	assert.Eq(t, "matched", ex.Outcome)
+	assert.Eq(t, []string{"header Accept-Version: 2"}, condStrings(ex.Route.Conditions()))
+
+	ex = r.Explain(GET, "/users/1")
+	assert.Eq(t, "matched", ex.Outcome)
+	assert.Empty(t, ex.Route.Conditions())
+	var failed ExplainStep
+	for _, s := range ex.Steps {
+		if s.Result == "condition failed" {
+			failed = s
+		}
+	}
+	assert.Eq(t, "conditions", failed.Source)
+	assert.Eq(t, "header Accept-Version: 2", failed.Detail)
+
+	ex = r.Explain(POST, "/users")
+	assert.Eq(t, "rejected", ex.Outcome)
+	assert.Eq(t, http.StatusUnsupportedMediaType, ex.Status)
+}
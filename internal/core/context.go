@@ -17,7 +17,7 @@ type Context struct {
 	// writer is the embedded response wrapper; Resp aliases &writer after Init.
 	writer responseWriter
 
-	// Path params, inlined for zero-allocation parameter passing.
+	// Path params; storage sized on Freeze and reused with the Context.
 	params Params
 
 	// Hot fields used by every request — typed, not in a map.
@@ -34,6 +34,12 @@ type Context struct {
 	// Errors accumulated during handling.
 	Errors []error
 
+	// Recovered panic value and stack, set before the panic handler runs.
+	recovered  any
+	panicStack []byte
+	// parent is the request context of the router that mounted this one.
+	parent *Context
+
 	// Lazy-init bag for arbitrary user data.
 	data map[string]any
 
@@ -54,6 +60,9 @@ func (c *Context) Init(w http.ResponseWriter, req *http.Request) {
 	if c.Errors != nil {
 		c.Errors = c.Errors[:0]
 	}
+	c.recovered = nil
+	c.panicStack = nil
+	c.parent = nil
 	if c.data != nil {
 		for k := range c.data {
 			delete(c.data, k)
@@ -142,19 +151,22 @@ func (c *Context) ReqCtxValue(key any) any {
 
 // ClientIP returns a best-effort client IP, consulting X-Forwarded-For,
 // X-Real-Ip, and finally the connection RemoteAddr.
-func (c *Context) ClientIP() string {
-	clientIP := c.Header("X-Forwarded-For")
-	if i := strings.IndexByte(clientIP, ','); i >= 0 {
-		clientIP = clientIP[:i]
+func (c *Context) ClientIP() string { return clientIP(c.Req) }
+
+// clientIP is Context.ClientIP for req.
+func clientIP(req *http.Request) string {
+	ip := req.Header.Get("X-Forwarded-For")
+	if i := strings.IndexByte(ip, ','); i >= 0 {
+		ip = ip[:i]
 	}
-	clientIP = strings.TrimSpace(clientIP)
-	if clientIP != "" {
-		return clientIP
+	ip = strings.TrimSpace(ip)
+	if ip != "" {
+		return ip
 	}
-	if ip := strings.TrimSpace(c.Header("X-Real-Ip")); ip != "" {
+	if ip := strings.TrimSpace(req.Header.Get("X-Real-Ip")); ip != "" {
 		return ip
 	}
-	if ip, _, err := net.SplitHostPort(strings.TrimSpace(c.Req.RemoteAddr)); err == nil {
+	if ip, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr)); err == nil {
 		return ip
 	}
 	return ""
@@ -196,7 +208,7 @@ func (c *Context) SafeGet(key string) any {
 // Param returns the value of the named path parameter, or "" if absent.
 func (c *Context) Param(name string) string { return c.params.Get(name) }
 
-// Params returns a pointer to the inlined params (avoids 16-Param value copy).
+// Params returns a pointer to the request params.
 func (c *Context) Params() *Params { return &c.params }
 
 // Route returns the matched Route or nil.
@@ -26,17 +26,18 @@ func IsDebug() bool {
 }
 
 // AnyMethods returns the canonical list of HTTP methods supported by the
-// router. The returned slice is shared — callers must not mutate it.
+// router, see Router.AnyMethods for those added by RegisterMethod. The
+// returned slice is shared — callers must not mutate it.
 func AnyMethods() []string {
-	return anyMethods
+	return stdMethods.all
 }
 
 // AllMethods is a synonym for AnyMethods, kept for backward compatibility.
 func AllMethods() []string {
-	return anyMethods
+	return AnyMethods()
 }
 
 // MethodsString returns the supported HTTP methods joined by commas.
 func MethodsString() string {
-	return strings.Join(anyMethods, ",")
+	return strings.Join(AnyMethods(), ",")
 }
@@ -15,13 +15,21 @@ import (
 const CTXAllowedMethods = "_allowedMethods"
 
 // CTXRecoverResult is the context key carrying the recover() return value
-// when OnPanic fires.
+// when a panic handler fires.
+//
+// Deprecated: use Context.Recovered.
 const CTXRecoverResult = "_recoverResult"
 
 var internal404Handler HandlerFunc = func(c *Context) {
 	http.NotFound(c.Resp, c.Req)
 }
 
+// Default 404 / 405 chains, used when NotFound / NotAllowed are not set.
+var (
+	internal404Chain = HandlersChain{internal404Handler}
+	internal405Chain = HandlersChain{internal405Handler}
+)
+
 var internal405Handler HandlerFunc = func(c *Context) {
 	if v, ok := c.Get(CTXAllowedMethods); ok {
 		if list, ok := v.([]string); ok {
@@ -97,18 +105,24 @@ func (r *Router) HandleContext(c *Context) {
 
 // handle is the core dispatch — runs middleware/route chain, falls back to
 // 404 / 405 handlers, and finally ensures a status code is written.
+//
+// Routes, hosts and the 404 / 405 chains come from the active route set
+// (see Swap), loaded once so a request never mixes two route sets.
+// Options and the OnError / OnPanic hooks come from r; route and group
+// hooks override them (see Context.resolveHook).
 func (r *Router) handle(ctx *Context) {
+	rt := r.current()
+
 	// Always flush status, even on a recovered panic path.
 	defer ctx.writer.ensureWriteHeader()
 
-	if r.OnPanic != nil {
-		defer func() {
-			if rec := recover(); rec != nil {
-				ctx.Set(CTXRecoverResult, rec)
-				r.OnPanic(ctx)
-			}
-		}()
-	}
+	// group is the group whose NotFound chain handles the request.
+	var group *RouteGroup
+	defer func() {
+		if rec := recover(); rec != nil {
+			ctx.recoverPanic(rec, ctx.matchedRoute, group)
+		}
+	}()
 
 	path := ctx.Req.URL.Path
 	if r.useEncodedPath {
@@ -117,92 +131,213 @@ func (r *Router) handle(ctx *Context) {
 	if r.interceptAll != "" {
 		path = r.interceptAll
 	} else {
-		path = r.formatPath(path)
+		path = r.requestPath(path)
 	}
 
-	method := ctx.Req.Method
-	idx := methodIndex(method)
+	d := r.decide(rt, ctx.Req, path, &ctx.params, nil)
+	switch d.kind {
+	case dispatchMatched:
+		ctx.matchedRoute = d.route
+		ctx.matchedPath = path
+	case dispatchRejected:
+		group = d.group
+		ctx.matchedPath = path
+		ctx.Fail(NewHTTPError(d.status, ""))
+	case dispatchRedirect:
+		redirectTo(ctx, d.location)
+	case dispatchOptions:
+		ctx.SetHeader("Allow", d.allowHeader)
+		ctx.Set(CTXAllowedMethods, d.allowed)
+	case dispatchNotAllowed:
+		ctx.Set(CTXAllowedMethods, d.allowed)
+	case dispatchNotFound:
+		group = d.group
+	}
+	if d.chain != nil {
+		ctx.SetHandlers(d.chain)
+		ctx.Next()
+	}
 
-	var route *Route
-	if idx >= 0 {
-		if m := r.staticRoutes[idx]; m != nil {
-			route = m[path]
+	if len(ctx.Errors) > 0 {
+		if h := ctx.resolveHook(errorHook, ctx.matchedRoute, group); h != nil {
+			h(ctx)
+		} else {
+			ProblemHandler(ctx)
 		}
-		if route == nil {
-			if tree := r.dynamicTrees[idx]; tree != nil {
-				if r2, ok := tree.lookup(path, &ctx.params); ok {
-					route = r2
-				}
+	}
+}
+
+// dispatchKind is the outcome of dispatching a request.
+type dispatchKind uint8
+
+const (
+	dispatchMatched dispatchKind = iota
+	dispatchRejected
+	dispatchRedirect
+	dispatchOptions
+	dispatchFallback
+	dispatchNotAllowed
+	dispatchNotFound
+)
+
+// dispatchKindNames are the Explanation outcomes of the dispatch kinds.
+var dispatchKindNames = [...]string{"matched", "rejected", "redirect", "options", "fallback",
+	"method not allowed", "not found"}
+
+func (k dispatchKind) String() string { return dispatchKindNames[k] }
+
+// decision is how a request is dispatched, see Router.decide.
+type decision struct {
+	kind dispatchKind
+	// route is the matched or fallback route, or the closest route of a
+	// rejected request.
+	route *Route
+	host  *hostRoutes
+	// group is the group of a rejected route, or whose NotFound chain
+	// handles the request.
+	group *RouteGroup
+	// chain runs for the request, nil for rejected and redirect outcomes.
+	chain HandlersChain
+	// status is the status of a rejected request, location the target of
+	// a redirect.
+	status   int
+	location string
+	// allowed and allowHeader are the methods of the path, for the
+	// options and method not allowed outcomes.
+	allowed     []string
+	allowHeader string
+}
+
+// decide matches the request req with the normalized path in the route
+// set rt and, on a miss, picks the redirect, OPTIONS, fallback, 405 or
+// 404 handling, in this order. Route params are bound to ps. Options come
+// from r. handle runs the decision, explain reports it, recording the
+// lookups in ex if not nil.
+func (r *Router) decide(rt *Router, req *http.Request, path string, ps *Params, ex *Explanation) decision {
+	method := req.Method
+	idx := rt.methods.index(method)
+
+	var d decision
+	var route *Route
+	if rt.hasHosts {
+		// Host params are bound first; tree lookups append after them.
+		if d.host = rt.matchHost(req.Host, ps); d.host != nil {
+			if ex != nil {
+				ex.MatchedHost = d.host.pattern
+				route = ex.lookup(&d.host.routeTable, d.host.pattern, idx, path, ps)
+			} else {
+				route = d.host.match(idx, path, ps)
 			}
 		}
 	}
+	if route == nil {
+		if ex != nil {
+			route = ex.lookup(&rt.routeTable, "", idx, path, ps)
+		} else {
+			route = rt.routeTable.match(idx, path, ps)
+		}
+	}
 
+	// A route with conditions that all failed is rejected with the status
+	// of the closest one, unless it is a 404.
+	if route != nil && route.variants != nil {
+		var closest *Route
+		if ex != nil {
+			route, closest, d.status = ex.pick(route, req)
+		} else {
+			route, closest, d.status = route.pick(req)
+		}
+		if route == nil && d.status != http.StatusNotFound {
+			d.kind, d.route, d.group = dispatchRejected, closest, closest.group
+			return d
+		}
+	}
 	if route != nil {
-		ctx.matchedRoute = route
-		ctx.matchedPath = path
-		ctx.SetHandlers(route.finalChain)
-		ctx.Next()
-	} else {
-		dispatched := false
-		if r.handleFallbackRoute && idx >= 0 {
-			if m := r.staticRoutes[idx]; m != nil {
-				if fb, ok := m["/*"]; ok {
-					ctx.SetHandlers(fb.finalChain)
-					ctx.Next()
-					dispatched = true
-				}
-			}
+		d.kind, d.route, d.chain = dispatchMatched, route, route.finalChain
+		return d
+	}
+
+	host := d.host
+	if ex != nil {
+		if host != nil {
+			ex.otherMethods(rt.methods, &host.routeTable, host.pattern, method, path)
 		}
-		if !dispatched && r.handleMethodNotAllowed {
-			allowed := r.findAllowedMethods(method, path)
-			if len(allowed) > 0 {
-				if len(r.noAllowed) == 0 {
-					r.noAllowed = HandlersChain{internal405Handler}
-				}
-				ctx.Set(CTXAllowedMethods, allowed)
-				ctx.SetHandlers(r.noAllowed)
-				ctx.Next()
-				dispatched = true
-			}
+		ex.otherMethods(rt.methods, &rt.routeTable, "", method, path)
+	}
+	if (r.redirectTrailingSlash || r.redirectFixedPath) && r.interceptAll == "" && method != CONNECT {
+		if to := r.redirectPath(rt, host, idx, path, ps); to != "" {
+			d.kind, d.location = dispatchRedirect, to
+			return d
+		}
+	}
+	if r.handleOptions && method == OPTIONS {
+		if e, ok := rt.allowedFor(host, path); ok {
+			d.kind, d.chain = dispatchOptions, rt.optionsHandlers()
+			d.allowed, d.allowHeader = e.methods, e.header
+			return d
 		}
-		if !dispatched {
-			if len(r.noRoute) == 0 {
-				r.noRoute = HandlersChain{internal404Handler}
+	}
+	if r.handleFallbackRoute {
+		if fb := rt.fallbackRoute(idx, req); fb != nil {
+			d.kind, d.route, d.chain = dispatchFallback, fb, fb.finalChain
+			return d
+		}
+	}
+	if r.handleMethodNotAllowed {
+		if allowed := rt.findAllowedMethods(host, method, path); len(allowed) > 0 {
+			d.kind, d.allowed, d.chain = dispatchNotAllowed, allowed, rt.noAllowed
+			if len(d.chain) == 0 {
+				d.chain = internal405Chain
 			}
-			ctx.SetHandlers(r.noRoute)
-			ctx.Next()
+			return d
 		}
 	}
 
-	if r.OnError != nil && len(ctx.Errors) > 0 {
-		r.OnError(ctx)
+	d.kind = dispatchNotFound
+	for _, g := range rt.groupNoRoutes {
+		if g.matchNotFound(host, path) {
+			d.group, d.chain = g, g.noRoute
+			return d
+		}
+	}
+	if host != nil && len(host.noRoute) > 0 {
+		d.chain = host.noRoute
+		return d
+	}
+	d.chain = rt.noRoute
+	if len(d.chain) == 0 {
+		d.chain = internal404Chain
+	}
+	return d
+}
+
+// fallbackRoute returns the "/*" route of HandleFallbackRoute for the
+// method idx, the variant picked for req if it has conditions or
+// variants, or nil.
+func (r *Router) fallbackRoute(idx int, req *http.Request) *Route {
+	if idx < 0 {
+		return nil
+	}
+	fb := r.staticRoutes[idx]["/*"]
+	if fb != nil && fb.variants != nil {
+		fb, _, _ = fb.pick(req)
 	}
+	return fb
 }
 
 // findAllowedMethods returns the set of HTTP methods (other than the
-// rejected method) that would match path. Used for the Allow header on 405.
-func (r *Router) findAllowedMethods(method, path string) []string {
+// rejected method) that would match path in the matched host's table or
+// the host-less table. Used for the Allow header on 405 and, with
+// HandleOptions, for OPTIONS.
+func (r *Router) findAllowedMethods(host *hostRoutes, method, path string) []string {
 	var allowed []string
-	for _, m := range []string{GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS, CONNECT, TRACE} {
-		if m == method {
-			continue
-		}
-		idx := methodIndex(m)
-		if idx < 0 {
-			continue
-		}
-		if r.staticRoutes[idx] != nil {
-			if _, ok := r.staticRoutes[idx][path]; ok {
-				allowed = append(allowed, m)
-				continue
-			}
-		}
-		if tree := r.dynamicTrees[idx]; tree != nil {
-			var ps Params
-			if _, ok := tree.lookup(path, &ps); ok {
-				allowed = append(allowed, m)
-			}
-		}
+	if host != nil {
+		allowed = host.allowedMethods(r.methods, allowed, method, path)
+	}
+	allowed = r.routeTable.allowedMethods(r.methods, allowed, method, path)
+	if r.handleOptions && len(allowed) > 0 && method != OPTIONS && !containsString(allowed, OPTIONS) {
+		// HandleOptions answers OPTIONS for the path.
+		allowed = append(allowed, OPTIONS)
 	}
 	return allowed
 }
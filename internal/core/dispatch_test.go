@@ -18,6 +18,22 @@ func textHandler(body string) HandlerFunc {
 	}
 }
 
+// serve serves a request for method and path with headers, given as
+// name, value pairs. The "Host" header sets the request host.
+func serve(r *Router, method, path string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		if headers[i] == "Host" {
+			req.Host = headers[i+1]
+		} else {
+			req.Header.Add(headers[i], headers[i+1])
+		}
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
 func TestServeHTTP_StaticHit(t *testing.T) {
 	r := New()
 	r.GET("/users", textHandler("hi"))
@@ -234,3 +250,54 @@ func TestListenUnix_RecordsRemoveOrListenErr(t *testing.T) {
 // Stop the orphan import deletion: net is needed by the Listen tests above.
 var _ = net.IPv4zero
 
+func TestServeHTTP_ConstrainedParam(t *testing.T) {
+	r := New()
+	r.GET(`/users/{id:\d+}`, func(c *Context) { c.Text(200, "id="+c.Param("id")) })
+	r.GET(`/users/{name:[a-z]+}`, func(c *Context) { c.Text(200, "name="+c.Param("name")) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/42", nil))
+	assert.Eq(t, "id=42", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/bob", nil))
+	assert.Eq(t, "name=bob", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/Bob1", nil))
+	assert.Eq(t, 404, w.Code)
+
+	// HEAD mirror keeps both constrained siblings.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("HEAD", "/users/bob", nil))
+	assert.Eq(t, 200, w.Code)
+}
+
+func TestRouteInfo_Constraints(t *testing.T) {
+	r := New()
+	route := r.GET(`/posts[/{id:[0-9]+}]`, func(c *Context) {})
+	assert.Eq(t, map[string]string{"id": "[0-9]+"}, route.Constraints())
+	assert.Eq(t, map[string]string{"id": "[0-9]+"}, r.Routes()[0].Constraints)
+
+	_, _, ok := r.Match(GET, "/posts/12")
+	assert.True(t, ok)
+	_, _, ok = r.Match(GET, "/posts/ab")
+	assert.False(t, ok)
+	_, _, ok = r.Match(GET, "/posts")
+	assert.True(t, ok)
+}
+
+func TestServeHTTP_OptionalSegment_RunsChain(t *testing.T) {
+	r := New()
+	r.Use(func(c *Context) { c.SetHeader("X-Global", "1"); c.Next() })
+	r.GET("/posts[/{id}]", func(c *Context) { c.Text(200, "id="+c.Param("id")) },
+		func(c *Context) { c.SetHeader("X-Route", "1"); c.Next() })
+
+	for path, want := range map[string]string{"/posts": "id=", "/posts/7": "id=7"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Eq(t, want, w.Body.String())
+		assert.Eq(t, "1", w.Header().Get("X-Global"))
+		assert.Eq(t, "1", w.Header().Get("X-Route"))
+	}
+}
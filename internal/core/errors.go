@@ -0,0 +1,266 @@
+package core
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/rux/v2/pkg/binding"
+	"github.com/gookit/rux/v2/pkg/render"
+)
+
+// Content types of RFC 7807 problem details.
+const (
+	ProblemJSON = "application/problem+json"
+	ProblemXML  = "application/problem+xml"
+)
+
+// HTTPError is an error with an HTTP status, a machine-readable code and
+// client-facing message and details. Cause is the wrapped internal error;
+// it is never sent to clients outside debug mode.
+//
+//	c.Fail(rux.NewHTTPError(404, "user not found").WithCode("user_not_found"))
+type HTTPError struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+	Cause   error
+
+	// stack is captured by NewHTTPError in debug mode.
+	stack []uintptr
+}
+
+// NewHTTPError creates an HTTPError. In debug mode the caller stack is
+// recorded and included in problem responses.
+func NewHTTPError(status int, message string) *HTTPError {
+	e := &HTTPError{Status: status, Message: message}
+	if debug {
+		var pcs [32]uintptr
+		n := runtime.Callers(2, pcs[:])
+		e.stack = pcs[:n]
+	}
+	return e
+}
+
+// WithCode sets the error code and returns e.
+func (e *HTTPError) WithCode(code string) *HTTPError {
+	e.Code = code
+	return e
+}
+
+// WithDetails sets the error details and returns e.
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	e.Details = details
+	return e
+}
+
+// WithCause sets the wrapped cause and returns e.
+func (e *HTTPError) WithCause(err error) *HTTPError {
+	e.Cause = err
+	return e
+}
+
+// Error implements error.
+func (e *HTTPError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.Status)
+	}
+	if e.Cause != nil {
+		return msg + ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the cause.
+func (e *HTTPError) Unwrap() error { return e.Cause }
+
+// StatusCode implements StatusCoder.
+func (e *HTTPError) StatusCode() int { return e.Status }
+
+// Stack returns the recorded stack frames, one "func file:line" per
+// entry. It is empty unless the error was created in debug mode.
+func (e *HTTPError) Stack() []string {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	var lines []string
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		f, more := frames.Next()
+		lines = append(lines, f.Function+" "+f.File+":"+strconv.Itoa(f.Line))
+		if !more {
+			break
+		}
+	}
+	return lines
+}
+
+// BindErrorMapper is the default Router.MapBindError: 400 with code
+// "invalid_request" and the decode error as message.
+func BindErrorMapper(err error) *HTTPError {
+	return NewHTTPError(http.StatusBadRequest, err.Error()).WithCode("invalid_request").WithCause(err)
+}
+
+// ValidationErrorMapper is the default Router.MapValidationError: 422 with
+// code "validation_failed" and the validator error as message.
+func ValidationErrorMapper(err error) *HTTPError {
+	return NewHTTPError(http.StatusUnprocessableEntity, err.Error()).WithCode("validation_failed").WithCause(err)
+}
+
+// ToHTTPError converts err to an *HTTPError:
+//
+//   - an *HTTPError in the chain is returned as is
+//   - binding.BindError and binding.ValidationError go through
+//     MapBindError and MapValidationError
+//   - a StatusCoder in the chain gives the status; the message is
+//     err.Error() below 500
+//   - anything else becomes a 500 without message
+//
+// err is kept as the cause of converted errors.
+func (r *Router) ToHTTPError(err error) *HTTPError {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		return he
+	}
+
+	var ve *binding.ValidationError
+	if errors.As(err, &ve) {
+		if r != nil && r.MapValidationError != nil {
+			return r.MapValidationError(ve.Err)
+		}
+		return ValidationErrorMapper(ve.Err)
+	}
+	var be *binding.BindError
+	if errors.As(err, &be) {
+		if r != nil && r.MapBindError != nil {
+			return r.MapBindError(be.Err)
+		}
+		return BindErrorMapper(be.Err)
+	}
+
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		status := sc.StatusCode()
+		var msg string
+		if status < http.StatusInternalServerError {
+			msg = err.Error()
+		}
+		return NewHTTPError(status, msg).WithCause(err)
+	}
+	return NewHTTPError(http.StatusInternalServerError, "").WithCause(err)
+}
+
+// Problem is an RFC 7807 problem details body, rendered by Context.Problem.
+// Details are not rendered as XML.
+type Problem struct {
+	XMLName xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type    string   `json:"type" xml:"type"`
+	Title   string   `json:"title" xml:"title"`
+	Status  int      `json:"status" xml:"status"`
+	Detail  string   `json:"detail,omitempty" xml:"detail,omitempty"`
+	// Instance is the request path.
+	Instance string `json:"instance,omitempty" xml:"instance,omitempty"`
+	Code     string `json:"code,omitempty" xml:"code,omitempty"`
+	Details  any    `json:"details,omitempty" xml:"-"`
+
+	// Debug mode only.
+	Cause string   `json:"cause,omitempty" xml:"cause,omitempty"`
+	Stack []string `json:"stack,omitempty" xml:"stack>frame,omitempty"`
+}
+
+// String implements fmt.Stringer for text/plain responses.
+func (p *Problem) String() string {
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(p.Status) + " " + p.Title)
+	if p.Detail != "" {
+		sb.WriteString(": " + p.Detail)
+	}
+	if p.Code != "" {
+		sb.WriteString(" (" + p.Code + ")")
+	}
+	if p.Cause != "" {
+		sb.WriteString("\ncause: " + p.Cause)
+	}
+	for _, line := range p.Stack {
+		sb.WriteString("\n    " + line)
+	}
+	return sb.String()
+}
+
+// newProblem builds the problem body of e.
+func newProblem(e *HTTPError, instance string) *Problem {
+	p := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code,
+		Details:  e.Details,
+	}
+	if debug {
+		if e.Cause != nil {
+			p.Cause = e.Cause.Error()
+		}
+		p.Stack = e.Stack()
+	}
+	return p
+}
+
+// Fail records err (see OnError) and aborts the handler chain. The
+// default OnError renders it as problem details.
+//
+//	if err != nil {
+//	    c.Fail(err)
+//	    return
+//	}
+func (c *Context) Fail(err error) {
+	c.AddError(err)
+	c.Abort()
+}
+
+// Problem writes err as an RFC 7807 problem details response, converted
+// by Router.ToHTTPError. The body is JSON (application/problem+json), XML
+// or text by the Accept header. In debug mode it includes the cause and
+// stack.
+func (c *Context) Problem(err error) {
+	e := c.router.ToHTTPError(err)
+	p := newProblem(e, c.Req.URL.Path)
+
+	for _, accept := range httpreq.ParseAccept(c.Req.Header.Get("Accept")) {
+		switch {
+		case accept == "application/xml" || accept == "text/xml" || accept == ProblemXML:
+			c.Resp.Header().Set(ContentType, ProblemXML)
+			c.Respond(e.Status, p, render.XMLRenderer{})
+			return
+		case accept == "text/plain":
+			c.Text(e.Status, fmt.Sprint(p))
+			return
+		case accept == "application/json" || strings.HasSuffix(accept, "+json"),
+			accept == "*/*" || accept == "application/*":
+			c.Resp.Header().Set(ContentType, ProblemJSON)
+			c.Respond(e.Status, p, render.JSONRenderer{})
+			return
+		}
+	}
+	c.Resp.Header().Set(ContentType, ProblemJSON)
+	c.Respond(e.Status, p, render.JSONRenderer{})
+}
+
+// ProblemHandler is the default OnError. It renders the first error as
+// problem details (see Context.Problem), unless a response status or
+// body was already written.
+func ProblemHandler(c *Context) {
+	err := c.FirstError()
+	if err == nil || c.writer.Written() || c.writer.Status() != 0 {
+		return
+	}
+	c.Problem(err)
+}
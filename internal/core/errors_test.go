@@ -0,0 +1,138 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+	"github.com/gookit/rux/v2/pkg/binding"
+)
+
+func TestHTTPError(t *testing.T) {
+	cause := errors.New("no rows")
+	e := NewHTTPError(http.StatusNotFound, "user not found").WithCode("user_not_found").WithCause(cause)
+	assert.Eq(t, "user not found: no rows", e.Error())
+	assert.Eq(t, 404, e.StatusCode())
+	assert.True(t, errors.Is(e, cause))
+	assert.Nil(t, e.Stack())
+
+	assert.Eq(t, "Bad Request", NewHTTPError(400, "").Error())
+}
+
+func TestRouter_ToHTTPError(t *testing.T) {
+	r := New()
+	he := NewHTTPError(409, "conflict")
+	assert.Same(t, he, r.ToHTTPError(errors.Join(errors.New("x"), he)))
+
+	e := r.ToHTTPError(&binding.BindError{Err: errors.New("bad json")})
+	assert.Eq(t, 400, e.Status)
+	assert.Eq(t, "invalid_request", e.Code)
+
+	e = r.ToHTTPError(&binding.ValidationError{Err: errors.New("name is required")})
+	assert.Eq(t, 422, e.Status)
+	assert.Eq(t, "name is required", e.Message)
+
+	e = r.ToHTTPError(teapotErr{})
+	assert.Eq(t, http.StatusTeapot, e.Status)
+	assert.Eq(t, "short and stout", e.Message)
+
+	e = r.ToHTTPError(errors.New("db password leaked"))
+	assert.Eq(t, 500, e.Status)
+	assert.Eq(t, "", e.Message)
+
+	r.MapValidationError = func(err error) *HTTPError {
+		return NewHTTPError(400, "invalid").WithDetails(err.Error())
+	}
+	e = r.ToHTTPError(&binding.ValidationError{Err: errors.New("name is required")})
+	assert.Eq(t, 400, e.Status)
+	assert.Eq(t, "name is required", e.Details)
+}
+
+func TestContext_Fail_DefaultOnError(t *testing.T) {
+	r := New()
+	r.GET("/users/{id}", func(c *Context) {
+		c.Text(200, "unreachable")
+	}, func(c *Context) {
+		c.Fail(NewHTTPError(404, "user not found").WithCode("user_not_found").
+			WithDetails(map[string]string{"id": c.Param("id")}))
+	})
+	r.POST("/users", func(c *Context) {
+		var in struct {
+			Name string `json:"name"`
+		}
+		if err := c.BindJSON(&in); err != nil {
+			c.Fail(err)
+		}
+	})
+	r.GET("/written", func(c *Context) {
+		c.Text(200, "ok")
+		c.AddError(errors.New("logged only"))
+	})
+
+	w := typedRequest(r, GET, "/users/7", "")
+	assert.Eq(t, 404, w.Code)
+	assert.Eq(t, ProblemJSON, w.Header().Get(ContentType))
+	var p map[string]any
+	assert.NoErr(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Eq(t, "about:blank", p["type"])
+	assert.Eq(t, "Not Found", p["title"])
+	assert.Eq(t, 404.0, p["status"])
+	assert.Eq(t, "user not found", p["detail"])
+	assert.Eq(t, "/users/7", p["instance"])
+	assert.Eq(t, "user_not_found", p["code"])
+	assert.Eq(t, map[string]any{"id": "7"}, p["details"])
+	assert.NotContainsKey(t, p, "cause")
+
+	w = typedRequest(r, POST, "/users", `{"name":`, "Content-Type", "application/json")
+	assert.Eq(t, 400, w.Code)
+	assert.StrContains(t, w.Body.String(), `"code":"invalid_request"`)
+
+	w = typedRequest(r, GET, "/written", "")
+	assert.Eq(t, 200, w.Code)
+	assert.Eq(t, "ok", w.Body.String())
+}
+
+func TestContext_Problem_Negotiation(t *testing.T) {
+	r := New()
+	r.GET("/fail", func(c *Context) {
+		c.Fail(NewHTTPError(403, "no access").WithCode("forbidden"))
+	})
+
+	w := typedRequest(r, GET, "/fail", "", "Accept", "application/xml")
+	assert.Eq(t, 403, w.Code)
+	assert.Eq(t, ProblemXML, w.Header().Get(ContentType))
+	assert.StrContains(t, w.Body.String(), `<problem xmlns="urn:ietf:rfc:7807">`)
+	assert.StrContains(t, w.Body.String(), "<detail>no access</detail>")
+
+	w = typedRequest(r, GET, "/fail", "", "Accept", "text/plain")
+	assert.StrContains(t, w.Header().Get(ContentType), "text/plain")
+	assert.Eq(t, "403 Forbidden: no access (forbidden)", w.Body.String())
+}
+
+func TestContext_Problem_Debug(t *testing.T) {
+	Debug(true)
+	defer Debug(false)
+
+	r := New()
+	r.GET("/boom", func(c *Context) {
+		c.Fail(errors.New("db password leaked"))
+	})
+	r.GET("/traced", func(c *Context) {
+		c.Fail(NewHTTPError(500, "").WithCause(errors.New("disk full")))
+	})
+
+	w := typedRequest(r, GET, "/boom", "")
+	assert.Eq(t, 500, w.Code)
+	var p Problem
+	assert.NoErr(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Eq(t, "db password leaked", p.Cause)
+	assert.Eq(t, "", p.Detail)
+
+	w = typedRequest(r, GET, "/traced", "", "Accept", "text/plain")
+	body := w.Body.String()
+	assert.StrContains(t, body, "cause: disk full")
+	assert.True(t, strings.Contains(body, "TestContext_Problem_Debug"))
+}
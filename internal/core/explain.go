@@ -0,0 +1,219 @@
+package core
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Explanation describes how the router dispatches a request, for
+// debugging unexpected 404 and 405 responses. See Router.Explain.
+type Explanation struct {
+	Method string `json:"method"`
+	Host   string `json:"host,omitempty"`
+	// Path is the request path, Normalized the path the route tables are
+	// searched with: formatted, or the InterceptAll path.
+	Path        string `json:"path"`
+	Normalized  string `json:"normalized"`
+	Intercepted bool   `json:"intercepted,omitempty"`
+	// MatchedHost is the host pattern whose routes were searched first.
+	MatchedHost string `json:"matched_host,omitempty"`
+	// Steps are the lookups done, in order.
+	Steps []ExplainStep `json:"steps"`
+	// Outcome is one of "matched", "rejected" (the conditions of the routes
+	// of the path failed, see Router.When), "redirect", "options",
+	// "fallback", "method not allowed" and "not found".
+	Outcome string `json:"outcome"`
+	// Status is the response status of a "rejected" outcome.
+	Status int `json:"status,omitempty"`
+	// Route is the matched route, Pattern its path in the route table.
+	Route   *Route            `json:"-"`
+	Pattern string            `json:"pattern,omitempty"`
+	Params  map[string]string `json:"params,omitempty"`
+	// Variant is the weighted variant picked for the request, see
+	// Router.Variant. Another request may get another one.
+	Variant string `json:"variant,omitempty"`
+	// Location is the target of a redirect outcome, Allowed the methods of
+	// a "method not allowed" or "options" outcome.
+	Location string   `json:"location,omitempty"`
+	Allowed  []string `json:"allowed,omitempty"`
+	// Handlers names the handler chain that runs, global middlewares first.
+	Handlers []string `json:"handlers"`
+	// Mounted explains the request in the router of a matched Mount route.
+	Mounted *Explanation `json:"mounted,omitempty"`
+}
+
+// ExplainStep is a single lookup of an Explanation.
+type ExplainStep struct {
+	// Source is "static" for the exact-path map, "tree" for the radix
+	// tree of dynamic routes, "conditions" for the conditions of a route
+	// sharing its method and path with others.
+	Source string `json:"source"`
+	// Host is the host pattern of the route table, "" for host-less routes.
+	Host   string `json:"host,omitempty"`
+	Method string `json:"method"`
+	// Pattern is the route or partial route pattern the step is about.
+	Pattern string `json:"pattern,omitempty"`
+	// Result is one of "matched", "not found" (no candidate at all),
+	// "prefix mismatch", "constraint failed", "incomplete" (the path ends
+	// or continues where no route does), "method mismatch" (a route of
+	// another method matches) and "condition failed".
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Explain reports how a request for method and path is dispatched: the
+// normalized path, the candidates looked up in the static map and the
+// radix tree, why each failed, and the handler chain that runs. Unlike
+// Match it follows the full dispatch, including redirects, automatic
+// OPTIONS, 405 and NotFound handlers. The router is frozen first.
+//
+// path may contain a query string. Host routes are not consulted; use
+// ExplainRequest for those.
+func (r *Router) Explain(method, path string) *Explanation {
+	u, err := url.Parse(path)
+	if err != nil {
+		u = &url.URL{Path: path}
+	}
+	return r.ExplainRequest(&http.Request{Method: strings.ToUpper(method), URL: u})
+}
+
+// ExplainRequest is Explain for req, with host routing.
+func (r *Router) ExplainRequest(req *http.Request) *Explanation {
+	if !r.frozen.Load() {
+		r.Freeze()
+	}
+	return r.explain(req)
+}
+
+// explain reports the decision of handle without running any handler.
+func (r *Router) explain(req *http.Request) *Explanation {
+	rt := r.current()
+
+	path := req.URL.Path
+	if r.useEncodedPath {
+		path = req.URL.EscapedPath()
+	}
+	ex := &Explanation{Method: req.Method, Host: req.Host, Path: path}
+	if r.interceptAll != "" {
+		path = r.interceptAll
+		ex.Intercepted = true
+	} else {
+		path = r.requestPath(path)
+	}
+	ex.Normalized = path
+
+	var ps Params
+	d := r.decide(rt, req, path, &ps, ex)
+	ex.Outcome = d.kind.String()
+	ex.Handlers = handlerNames(d.chain)
+	switch d.kind {
+	case dispatchMatched, dispatchFallback:
+		ex.Route = d.route
+		ex.Pattern = d.route.path
+		ex.Variant = d.route.Variant()
+	case dispatchRejected:
+		ex.Status = d.status
+		ex.Route = d.route
+		ex.Pattern = d.route.path
+	case dispatchRedirect:
+		ex.Location = d.location
+	case dispatchOptions, dispatchNotAllowed:
+		ex.Allowed = d.allowed
+	}
+	if d.kind != dispatchMatched {
+		return ex
+	}
+
+	if ps.n > 0 {
+		ex.Params = make(map[string]string, ps.n)
+		for _, p := range ps.Snapshot() {
+			ex.Params[p.Key] = p.Value
+		}
+	}
+	if sub := d.route.mounted; sub != nil {
+		subReq := new(http.Request)
+		*subReq = *req
+		subReq.URL = &url.URL{Path: "/" + ps.Get(mountParam), RawQuery: req.URL.RawQuery}
+		ex.Mounted = sub.ExplainRequest(subReq)
+	}
+	return ex
+}
+
+// lookup is routeTable.match recording the steps taken.
+func (ex *Explanation) lookup(t *routeTable, host string, idx int, path string, ps *Params) *Route {
+	step := ExplainStep{Source: "static", Host: host, Method: ex.Method, Pattern: path}
+	if idx < 0 {
+		step.Result = "not found"
+		step.Detail = "unknown method"
+		ex.Steps = append(ex.Steps, step)
+		return nil
+	}
+	if route, ok := t.staticRoutes[idx][path]; ok {
+		step.Result = "matched"
+		ex.Steps = append(ex.Steps, step)
+		return route
+	}
+	step.Result = "not found"
+	ex.Steps = append(ex.Steps, step)
+
+	tree := t.dynamicTrees[idx]
+	if tree == nil {
+		ex.Steps = append(ex.Steps, ExplainStep{Source: "tree", Host: host, Method: ex.Method,
+			Result: "not found", Detail: "no dynamic routes"})
+		return nil
+	}
+	tr := &walkTrace{fail: func(pattern, result, detail string) {
+		ex.Steps = append(ex.Steps, ExplainStep{Source: "tree", Host: host, Method: ex.Method,
+			Pattern: pattern, Result: result, Detail: detail})
+	}}
+	snap := ps.n
+	route, ok := walkNode(tree.root, path, ps, tr)
+	if !ok {
+		ps.n = snap
+		return nil
+	}
+	ex.Steps = append(ex.Steps, ExplainStep{Source: "tree", Host: host, Method: ex.Method,
+		Pattern: route.path, Result: "matched"})
+	return route
+}
+
+// pick is Route.pick recording the conditions checked.
+func (ex *Explanation) pick(slot *Route, req *http.Request) (*Route, *Route, int) {
+	for _, v := range slot.variants {
+		step := ExplainStep{Source: "conditions", Host: v.host, Method: ex.Method,
+			Pattern: strings.Join(condStrings(v.conds), ", "), Result: "matched"}
+		if _, failed := v.check(req); failed != nil {
+			step.Result = "condition failed"
+			step.Detail = failed.desc
+		}
+		ex.Steps = append(ex.Steps, step)
+		if step.Result == "matched" {
+			break
+		}
+	}
+	return slot.pick(req)
+}
+
+// otherMethods records the routes of other methods matching path in t.
+func (ex *Explanation) otherMethods(ms *methodSet, t *routeTable, host, method, path string) {
+	for _, m := range ms.all {
+		idx := ms.index(m)
+		if m == method || idx < 0 {
+			continue
+		}
+		step := ExplainStep{Host: host, Method: m, Result: "method mismatch"}
+		if route, ok := t.staticRoutes[idx][path]; ok {
+			step.Source, step.Pattern = "static", route.path
+			ex.Steps = append(ex.Steps, step)
+			continue
+		}
+		if tree := t.dynamicTrees[idx]; tree != nil {
+			var ps Params
+			if route, ok := tree.lookup(path, &ps); ok {
+				step.Source, step.Pattern = "tree", route.path
+				ex.Steps = append(ex.Steps, step)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestRouter_Explain_Matched(t *testing.T) {
+	r := New()
+	r.Use(textHandler("global"))
+	r.GET("/users/{id:\\d+}/posts", textHandler("posts"))
+	r.GET("/users/new", textHandler("new"))
+
+	ex := r.Explain("get", "/users/42/posts/?page=1")
+	assert.Eq(t, GET, ex.Method)
+	assert.Eq(t, "/users/42/posts", ex.Normalized)
+	assert.Eq(t, "matched", ex.Outcome)
+	assert.Eq(t, `/users/{id:\d+}/posts`, ex.Pattern)
+	assert.Eq(t, map[string]string{"id": "42"}, ex.Params)
+	assert.Len(t, ex.Handlers, 2)
+
+	assert.Eq(t, "static", ex.Steps[0].Source)
+	assert.Eq(t, "not found", ex.Steps[0].Result)
+	last := ex.Steps[len(ex.Steps)-1]
+	assert.Eq(t, "tree", last.Source)
+	assert.Eq(t, "matched", last.Result)
+}
+
+func TestRouter_Explain_Failures(t *testing.T) {
+	r := New()
+	r.GET("/users/{id:\\d+}", textHandler("user"))
+	r.GET("/users/{id:\\d+}/posts", textHandler("posts"))
+	r.POST("/users/{name}", textHandler("create"))
+	r.NotFound(textHandler("nf"))
+
+	ex := r.Explain(GET, "/users/abc")
+	assert.Eq(t, "not found", ex.Outcome)
+	assert.Len(t, ex.Handlers, 1)
+
+	results := make(map[string]ExplainStep)
+	for _, s := range ex.Steps {
+		results[s.Result] = s
+	}
+	assert.Eq(t, `/users/{id:\d+}`, results["constraint failed"].Pattern)
+	assert.Eq(t, `"abc" does not match \d+`, results["constraint failed"].Detail)
+	assert.Eq(t, POST, results["method mismatch"].Method)
+	assert.Eq(t, "/users/:name", results["method mismatch"].Pattern)
+
+	ex = r.Explain(GET, "/users/1/comments")
+	assert.Eq(t, "not found", ex.Outcome)
+	var mismatch bool
+	for _, s := range ex.Steps {
+		if s.Result == "prefix mismatch" {
+			mismatch = true
+			assert.Eq(t, `/users/{id:\d+}/posts`, s.Pattern)
+		}
+	}
+	assert.True(t, mismatch)
+}
+
+func TestRouter_Explain_DispatchOutcomes(t *testing.T) {
+	r := New(HandleMethodNotAllowed, HandleOptions, RedirectTrailingSlash)
+	r.PUT("/items/{id}", textHandler("put"))
+	r.GET("/list", textHandler("list"))
+
+	ex := r.Explain(GET, "/items/1")
+	assert.Eq(t, "method not allowed", ex.Outcome)
+	assert.Eq(t, []string{PUT, OPTIONS}, ex.Allowed)
+
+	ex = r.Explain(OPTIONS, "/items/1")
+	assert.Eq(t, "options", ex.Outcome)
+	assert.Eq(t, []string{OPTIONS, PUT}, ex.Allowed)
+
+	ex = r.Explain(GET, "/list/")
+	assert.Eq(t, "redirect", ex.Outcome)
+	assert.Eq(t, "/list", ex.Location)
+
+	r2 := New(InterceptAll("/list"))
+	r2.GET("/list", textHandler("list"))
+	ex = r2.Explain(GET, "/anything")
+	assert.True(t, ex.Intercepted)
+	assert.Eq(t, "matched", ex.Outcome)
+}
+
+func TestRouter_ExplainRequest_HostsAndMounts(t *testing.T) {
+	sub := New()
+	sub.GET("/stats", textHandler("stats"))
+
+	r := New()
+	r.Host("api.example.com", func() {
+		r.Mount("/admin", sub)
+	})
+
+	req := httptest.NewRequest(GET, "http://api.example.com/admin/stats", nil)
+	ex := r.ExplainRequest(req)
+	assert.Eq(t, "api.example.com", ex.MatchedHost)
+	assert.Eq(t, "matched", ex.Outcome)
+	assert.NotNil(t, ex.Mounted)
+	assert.Eq(t, "/stats", ex.Mounted.Normalized)
+	assert.Eq(t, "matched", ex.Mounted.Outcome)
+
+	bs, err := json.Marshal(ex)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), `"mounted":{"method":"GET"`)
+}
@@ -0,0 +1,342 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportedRoute is the machine-readable description of a route, see
+// Router.ExportRoutes.
+type ExportedRoute struct {
+	Methods []string `json:"methods" yaml:"methods"`
+	// Path is the path as registered, with {name} placeholders and optional
+	// segments.
+	Path    string `json:"path" yaml:"path"`
+	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+	Handler string `json:"handler" yaml:"handler"`
+	// Middlewares are the names of the effective middlewares, in run order.
+	Middlewares []string          `json:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	Host        string            `json:"host,omitempty" yaml:"host,omitempty"`
+	Constraints map[string]string `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	// Meta is the route metadata, group metadata included.
+	Meta       map[string]any `json:"meta,omitempty" yaml:"meta,omitempty"`
+	Conditions []string       `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Version    string         `json:"version,omitempty" yaml:"version,omitempty"`
+	Variant    string         `json:"variant,omitempty" yaml:"variant,omitempty"`
+	Weight     int            `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// ExportedRoutes is a route table exported by Router.ExportRoutes or read
+// by ParseRoutes.
+type ExportedRoutes []ExportedRoute
+
+// ExportRoutes describes the registered routes in registration order, as
+// Routes does, for review and for tooling such as gateway config
+// generators. Mounted sub-router routes follow their mount route with the
+// mount prefix prepended.
+//
+//	data, _ := r.ExportRoutes().JSON() // or YAML()
+func (r *Router) ExportRoutes() ExportedRoutes {
+	if rt := r.current(); rt != r {
+		return rt.ExportRoutes()
+	}
+	routes := r.listedRoutes()
+	out := make(ExportedRoutes, 0, len(routes))
+	for _, route := range routes {
+		out = append(out, route.export(r.effectiveChain(route)))
+		if sub := route.mounted; sub != nil {
+			prefix := strings.TrimRight(route.MountPrefix(), "/")
+			for _, er := range sub.ExportRoutes() {
+				er.Path = prefix + er.Path
+				out = append(out, er)
+			}
+		}
+	}
+	return out
+}
+
+// export describes r, dispatched with chain.
+func (r *Route) export(chain HandlersChain) ExportedRoute {
+	path := r.originalPath
+	if path == "" {
+		path = r.path
+	}
+	er := ExportedRoute{
+		Methods:     r.methods,
+		Path:        path,
+		Name:        r.name,
+		Handler:     r.HandlerName(),
+		Host:        r.host,
+		Constraints: r.constraints,
+		Conditions:  condStrings(r.conds),
+		Version:     r.version,
+		Variant:     r.Variant(),
+		Weight:      r.Weight(),
+	}
+	if meta := r.Meta(); len(meta) > 0 {
+		er.Meta = meta
+	}
+	if len(chain) > 1 {
+		er.Middlewares = handlerNames(chain[:len(chain)-1])
+	}
+	return er
+}
+
+// JSON returns the routes as an indented JSON array.
+func (rs ExportedRoutes) JSON() ([]byte, error) {
+	return json.MarshalIndent(rs, "", "  ")
+}
+
+// YAML returns the routes as a YAML sequence, with the keys of JSON.
+func (rs ExportedRoutes) YAML() ([]byte, error) {
+	return yaml.Marshal(rs)
+}
+
+// Text returns the routes in a line format for diffing route tables
+// between releases: a line per method and route, sorted by path and
+// method, with the set fields as key=value pairs.
+//
+//	GET /users/{id} name=user_show handler=main.showUser middlewares=main.auth
+func (rs ExportedRoutes) Text() string {
+	var lines []string
+	for _, er := range rs {
+		var sb strings.Builder
+		writeField := func(key, val string) {
+			if val != "" {
+				sb.WriteString(" " + key + "=" + val)
+			}
+		}
+		writeField("host", er.Host)
+		writeField("name", er.Name)
+		writeField("handler", er.Handler)
+		writeField("middlewares", strings.Join(er.Middlewares, ","))
+		writeField("conditions", strings.Join(er.Conditions, ","))
+		writeField("version", er.Version)
+		writeField("variant", er.Variant)
+		if er.Weight > 0 {
+			writeField("weight", strconv.Itoa(er.Weight))
+		}
+		for _, key := range sortedKeys(er.Meta) {
+			data, err := json.Marshal(er.Meta[key])
+			if err != nil {
+				data = []byte(goutil.String(er.Meta[key]))
+			}
+			writeField("meta."+key, string(data))
+		}
+		for _, m := range er.Methods {
+			lines = append(lines, m+" "+er.Path+sb.String())
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		mi, pi, _ := strings.Cut(lines[i], " ")
+		mj, pj, _ := strings.Cut(lines[j], " ")
+		if pi != pj {
+			return pi < pj
+		}
+		return mi < mj
+	})
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ParseRoutes reads routes written by ExportedRoutes.JSON or YAML, or by
+// hand in either format. Data starting with '[' is read as JSON.
+func ParseRoutes(data []byte) (ExportedRoutes, error) {
+	var routes ExportedRoutes
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &routes)
+	} else {
+		err = yaml.Unmarshal(data, &routes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rux: parse routes: %w", err)
+	}
+	return routes, nil
+}
+
+// HandlerRegistry maps the handler and middleware names of exported
+// routes to their funcs, see Router.ImportRoutes.
+type HandlerRegistry map[string]HandlerFunc
+
+// Add registers handlers under their func names, the names ExportRoutes
+// reports; method values are named after their method, pkg.(*T).Method,
+// as Resource and AutoRoute routes report them.
+//
+// It panics on a name registered already, e.g. two closures of one func
+// literal, and on names shared by unrelated funcs: those of Typed
+// handlers and of method values made by reflection. Register those with
+// Set.
+func (hr HandlerRegistry) Add(handlers ...HandlerFunc) HandlerRegistry {
+	for _, h := range handlers {
+		name := funcName(h)
+		if name == "reflect.methodValueCall" || strings.Contains(name, "[...]") {
+			panic(fmt.Sprintf("rux: HandlerRegistry: %s does not name a single func, register it with Set", name))
+		}
+		hr.Set(name, h)
+	}
+	return hr
+}
+
+// Set registers h under name, e.g. a Typed handler under the name of its
+// func, which AddTyped routes report:
+//
+//	reg.Set("main.getUser", rux.Typed(getUser))
+//
+// It panics on a name registered already.
+func (hr HandlerRegistry) Set(name string, h HandlerFunc) HandlerRegistry {
+	if _, dup := hr[name]; dup {
+		panic(fmt.Sprintf("rux: HandlerRegistry: duplicate handler name %s", name))
+	}
+	hr[name] = h
+	return hr
+}
+
+// ImportRoutes registers routes, binding their handler and middleware
+// names through reg. Middlewares of the router's Use chain are skipped,
+// the others are added as route middlewares; metadata is set with
+// Route.With and variants with Router.Variant.
+//
+// Routes with conditions cannot be imported. On an unknown name, such a
+// route or a route that cannot be registered, as a duplicate,
+// ImportRoutes returns an error and registers no route.
+func (r *Router) ImportRoutes(routes ExportedRoutes, reg HandlerRegistry) error {
+	global := make(map[string]bool, len(r.globalChain))
+	for _, name := range handlerNames(r.globalChain) {
+		global[name] = true
+	}
+
+	list := make([]importedRoute, 0, len(routes))
+	for i, er := range routes {
+		methods := strings.Join(er.Methods, ",")
+		if methods == "" {
+			methods = GET
+		}
+		where := fmt.Sprintf("route %d (%s %s)", i+1, methods, er.Path)
+		if len(er.Conditions) > 0 {
+			return fmt.Errorf("rux: import %s: routes with conditions cannot be imported", where)
+		}
+		h, ok := reg[er.Handler]
+		if !ok {
+			return fmt.Errorf("rux: import %s: unknown handler %q", where, er.Handler)
+		}
+		im := importedRoute{er: er, where: where, handler: h}
+		for _, name := range er.Middlewares {
+			if global[name] {
+				continue
+			}
+			mw, ok := reg[name]
+			if !ok {
+				return fmt.Errorf("rux: import %s: unknown middleware %q", where, name)
+			}
+			im.middles = append(im.middles, mw)
+		}
+		list = append(list, im)
+	}
+
+	snap := r.snapshotRoutes()
+	for _, im := range list {
+		if err := r.importRoute(im); err != nil {
+			r.restoreRoutes(snap)
+			return err
+		}
+	}
+	return nil
+}
+
+// importedRoute is a route of ImportRoutes with its funcs bound.
+type importedRoute struct {
+	er      ExportedRoute
+	where   string
+	handler HandlerFunc
+	middles HandlersChain
+}
+
+// importRoute registers im, returning a registration panic as an error.
+func (r *Router) importRoute(im importedRoute) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("rux: import %s: %s", im.where, strings.TrimPrefix(fmt.Sprint(rec), "rux: "))
+		}
+	}()
+	g := r.group()
+	if im.er.Host != "" {
+		g = g.Host(im.er.Host)
+	}
+	if im.er.Variant != "" {
+		g = g.Variant(im.er.Variant, im.er.Weight)
+	}
+	route := g.AddNamed(im.er.Name, im.er.Path, im.handler, im.er.Methods...)
+	route.setHandlerName(im.er.Handler)
+	route.Use(im.middles...)
+	for _, key := range sortedKeys(im.er.Meta) {
+		route.With(key, im.er.Meta[key])
+	}
+	return nil
+}
+
+// routesSnapshot is the route registration state of a router, for
+// ImportRoutes to roll back to.
+type routesSnapshot struct {
+	table        routeTable
+	exactHosts   map[string]*hostRoutes
+	patternHosts []*hostRoutes
+	hasHosts     bool
+	namedRoutes  map[string]*Route
+	routes       int
+	counter      int
+}
+
+// snapshotRoutes returns a copy of the route registration state of r.
+func (r *Router) snapshotRoutes() routesSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snap := routesSnapshot{
+		table:       r.routeTable.clone(),
+		hasHosts:    r.hasHosts,
+		namedRoutes: maps.Clone(r.namedRoutes),
+		routes:      len(r.routeList),
+		counter:     r.counter,
+	}
+	if r.exactHosts != nil {
+		snap.exactHosts = make(map[string]*hostRoutes, len(r.exactHosts))
+		for pattern, h := range r.exactHosts {
+			snap.exactHosts[pattern] = h.clone()
+		}
+	}
+	for _, h := range r.patternHosts {
+		snap.patternHosts = append(snap.patternHosts, h.clone())
+	}
+	return snap
+}
+
+// restoreRoutes resets the route registration state of r to snap.
+func (r *Router) restoreRoutes(snap routesSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routeTable = snap.table
+	r.exactHosts = snap.exactHosts
+	r.patternHosts = snap.patternHosts
+	r.hasHosts = snap.hasHosts
+	r.namedRoutes = snap.namedRoutes
+	clear(r.routeList[snap.routes:])
+	r.routeList = r.routeList[:snap.routes]
+	r.counter = snap.counter
+}
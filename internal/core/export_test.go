@@ -0,0 +1,179 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func exportShow(c *Context) { c.Text(200, "show "+c.Param("id")+" "+c.Resp.Header().Get("X-Auth")) }
+func exportList(c *Context) { c.Text(200, "list") }
+func exportAuth(c *Context) { c.Resp.Header().Set("X-Auth", "ok"); c.Next() }
+func exportLog(c *Context)  { c.Next() }
+
+func newExportRouter() *Router {
+	r := New()
+	r.Use(exportLog)
+	api := r.NewGroup("/api", exportAuth).With("tier", 2)
+	api.AddNamed("user_show", `/users/{id:\d+}`, exportShow).With("scope", "users:read")
+	api.GET("/posts[/{slug}]", exportList)
+	r.Host("admin.example.com", func() {
+		r.GET("/stats", exportList)
+	})
+	return r
+}
+
+func TestRouter_ExportRoutes(t *testing.T) {
+	routes := newExportRouter().ExportRoutes()
+	assert.Len(t, routes, 3)
+
+	show := routes[0]
+	assert.Eq(t, []string{GET}, show.Methods)
+	assert.Eq(t, `/api/users/{id:\d+}`, show.Path)
+	assert.Eq(t, "user_show", show.Name)
+	assert.True(t, strings.HasSuffix(show.Handler, "core.exportShow"))
+	assert.Len(t, show.Middlewares, 2)
+	assert.True(t, strings.HasSuffix(show.Middlewares[0], "core.exportLog"))
+	assert.Eq(t, map[string]string{"id": `\d+`}, show.Constraints)
+	assert.Eq(t, map[string]any{"tier": 2, "scope": "users:read"}, show.Meta)
+	assert.Eq(t, "/api/posts[/{slug}]", routes[1].Path)
+	assert.Eq(t, "admin.example.com", routes[2].Host)
+
+	text := routes.Text()
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "GET /api/posts[/{slug}] handler="), lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], `GET /api/users/{id:\d+} name=user_show handler=`), lines[1])
+	assert.True(t, strings.HasSuffix(lines[1], ` meta.scope="users:read" meta.tier=2`), lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "GET /stats host=admin.example.com handler="), lines[2])
+}
+
+func TestRouter_ImportRoutes(t *testing.T) {
+	src := newExportRouter()
+	reg := HandlerRegistry{}.Add(exportShow, exportList, exportAuth)
+
+	for _, encode := range []func(ExportedRoutes) ([]byte, error){ExportedRoutes.JSON, ExportedRoutes.YAML} {
+		data, err := encode(src.ExportRoutes())
+		assert.NoErr(t, err)
+		routes, err := ParseRoutes(data)
+		assert.NoErr(t, err)
+
+		r := New()
+		r.Use(exportLog)
+		assert.NoErr(t, r.ImportRoutes(routes, reg))
+		assert.Eq(t, src.ExportRoutes().Text(), r.ExportRoutes().Text())
+
+		assert.Eq(t, "show 7 ok", serve(r, GET, "/api/users/7").Body.String())
+		assert.Eq(t, 404, serve(r, GET, "/api/users/x").Code)
+		assert.Eq(t, "list", serve(r, GET, "/api/posts/hello").Body.String())
+		assert.Eq(t, "list", serve(r, GET, "/stats", "Host", "admin.example.com").Body.String())
+		scope, _ := MetaOf[string](r.GetRoute("user_show"), "scope")
+		assert.Eq(t, "users:read", scope)
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes([]byte(`[
+		{"methods": ["GET", "HEAD"], "path": "/users/{id}", "name": "user_show", "handler": "show", "meta": {"ttl": 60}},
+		{"path": "/ping", "handler": "ping"}
+	]`))
+	assert.NoErr(t, err)
+	assert.Len(t, routes, 2)
+	assert.Eq(t, []string{"GET", "HEAD"}, routes[0].Methods)
+	assert.Eq(t, "/users/{id}", routes[0].Path)
+	assert.Eq(t, float64(60), routes[0].Meta["ttl"])
+	assert.Eq(t, "ping", routes[1].Handler)
+
+	routes, err = ParseRoutes([]byte(`
+# hand-written
+- methods: [GET, HEAD]
+  path: /users/{id}
+  handler: show
+  meta: {ttl: 60}
+`))
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"GET", "HEAD"}, routes[0].Methods)
+	assert.Eq(t, 60, routes[0].Meta["ttl"])
+
+	_, err = ParseRoutes([]byte("path: /x\n"))
+	assert.Err(t, err)
+	_, err = ParseRoutes([]byte(`[{"weight": "heavy"}]`))
+	assert.Err(t, err)
+}
+
+func TestRouter_ImportRoutes_Errors(t *testing.T) {
+	r := New()
+	reg := HandlerRegistry{"show": exportShow}
+	err := r.ImportRoutes(ExportedRoutes{
+		{Methods: []string{GET}, Path: "/a", Handler: "show"},
+		{Methods: []string{GET}, Path: "/b", Handler: "missing"},
+	}, reg)
+	assert.Eq(t, `rux: import route 2 (GET /b): unknown handler "missing"`, err.Error())
+	assert.Len(t, r.Routes(), 0)
+
+	err = r.ImportRoutes(ExportedRoutes{{Path: "/c", Handler: "show", Middlewares: []string{"auth"}}}, reg)
+	assert.Eq(t, `rux: import route 1 (GET /c): unknown middleware "auth"`, err.Error())
+	err = r.ImportRoutes(ExportedRoutes{{Path: "/d", Handler: "show", Conditions: []string{"header X=1"}}}, reg)
+	assert.Eq(t, "rux: import route 1 (GET /d): routes with conditions cannot be imported", err.Error())
+
+	// A route failing to register rolls the import back.
+	r.GET("/b", textHandler("b"))
+	r.GET("/users/{id}", textHandler("user"))
+	err = r.ImportRoutes(ExportedRoutes{
+		{Methods: []string{GET}, Path: "/a", Name: "a", Handler: "show"},
+		{Methods: []string{GET}, Path: "/users/{uid}/posts", Handler: "show", Host: "api.example.com"},
+		{Methods: []string{GET}, Path: "/b", Handler: "show"},
+	}, reg)
+	assert.Eq(t, "rux: import route 3 (GET /b): duplicate static route: GET /b", err.Error())
+	err = r.ImportRoutes(ExportedRoutes{{Methods: []string{GET}, Path: "/users/{uid}/posts", Handler: "show"}}, reg)
+	assert.Eq(t, `rux: import route 1 (GET /users/{uid}/posts): conflicting param names "id" vs "uid" at /users/:uid/posts`,
+		err.Error())
+	assert.Len(t, r.Routes(), 2)
+	assert.Nil(t, r.GetRoute("a"))
+	assert.Eq(t, 404, serve(r, GET, "/a").Code)
+	assert.Eq(t, 404, serve(r, GET, "/users/1/posts", "Host", "api.example.com").Code)
+	assert.Eq(t, "b", serve(r, GET, "/b").Body.String())
+	assert.Eq(t, "user", serve(r, GET, "/users/1").Body.String())
+}
+
+func TestHandlerRegistry(t *testing.T) {
+	u := &autoUsers{}
+	reg := HandlerRegistry{}.Add(exportShow, u.GetUsers)
+	assert.NotNil(t, reg["github.com/gookit/rux/v2/internal/core.(*autoUsers).GetUsers"])
+
+	assert.PanicsMsg(t, func() {
+		reg.Add(exportShow)
+	}, "rux: HandlerRegistry: duplicate handler name github.com/gookit/rux/v2/internal/core.exportShow")
+	// Closures of one func literal share its name.
+	assert.Panics(t, func() {
+		HandlerRegistry{}.Add(textHandler("a"), textHandler("b"))
+	})
+	assert.PanicsMsg(t, func() {
+		reg.Add(Typed(echoTyped))
+	}, "rux: HandlerRegistry: github.com/gookit/rux/v2/internal/core.Typed[...].func1 does not name a single func, register it with Set")
+	assert.PanicsMsg(t, func() {
+		reg.Set("show", exportShow).Set("show", exportList)
+	}, "rux: HandlerRegistry: duplicate handler name show")
+}
+
+func TestRouter_ImportRoutes_MethodHandlers(t *testing.T) {
+	src := New()
+	src.ResourceWith("/api", &postRes{}, ResourceOptions{Name: "posts", APIOnly: true})
+	AddTyped(src, "/echo/{id}", echoTyped, POST)
+
+	routes := src.ExportRoutes()
+	assert.Eq(t, "github.com/gookit/rux/v2/internal/core.(*postRes).Show", src.GetRoute("posts_show").HandlerName())
+	assert.Eq(t, "github.com/gookit/rux/v2/internal/core.echoTyped", routes[3].Handler)
+
+	p := &postRes{}
+	reg := HandlerRegistry{}.Add(p.Index, p.Store, p.Show).
+		Set("github.com/gookit/rux/v2/internal/core.echoTyped", Typed(echoTyped))
+	r := New()
+	assert.NoErr(t, r.ImportRoutes(routes, reg))
+	assert.Eq(t, routes.Text(), r.ExportRoutes().Text())
+	assert.Eq(t, "posts of ", serve(r, GET, "/api/posts").Body.String())
+	assert.Eq(t, "store post of ", serve(r, POST, "/api/posts").Body.String())
+	assert.Eq(t, "post 7", serve(r, GET, "/api/posts/7").Body.String())
+	assert.Eq(t, 200, typedRequest(r, POST, "/echo/3", `{"name":"x"}`, "Content-Type", "application/json").Code)
+}
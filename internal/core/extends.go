@@ -3,7 +3,6 @@ package core
 import (
 	"io"
 	"net/url"
-	"regexp"
 	"strings"
 
 	"github.com/gookit/goutil"
@@ -12,9 +11,6 @@ import (
 // M is a generic map type used for params, payloads, and URL builders.
 type M map[string]any
 
-// varRegex matches path variables like {id} or {uid:\d+}
-var varRegex = regexp.MustCompile(`{[^/]+}`)
-
 /*************************************************************
  * Extends interfaces definition
  *************************************************************/
@@ -87,6 +83,15 @@ func (b *BuildRequestURL) Path(path string) *BuildRequestURL {
 	return b
 }
 
+// hostTpl sets a route host pattern as the host, unless a host is already
+// set or the pattern has a wildcard label.
+func (b *BuildRequestURL) hostTpl(pattern string) *BuildRequestURL {
+	if b.host == "" && pattern != "" && strings.IndexByte(pattern, '*') == -1 {
+		b.host = pattern
+	}
+	return b
+}
+
 // Build url. Keys in withParams that look like "{name}" populate path params;
 // any other key becomes a query string parameter.
 func (b *BuildRequestURL) Build(withParams ...M) *url.URL {
@@ -110,32 +115,41 @@ func (b *BuildRequestURL) Build(withParams ...M) *url.URL {
 	u.Path = path
 	u.RawQuery = b.queries.Encode()
 
-	ss := varRegex.FindAllString(path, -1)
-
-	if len(ss) == 0 {
-		return u
-	}
-
-	var n string
-	var varParams = make(map[string]string)
-
-	for _, str := range ss {
-		nvStr := str[1 : len(str)-1]
-
-		if strings.IndexByte(nvStr, ':') > 0 {
-			nv := strings.SplitN(nvStr, ":", 2)
-			n, _ = strings.TrimSpace(nv[0]), strings.TrimSpace(nv[1])
-			varParams[str] = "{" + n + "}"
-		} else {
-			varParams[str] = str
+	if strings.IndexByte(u.Host, '{') != -1 {
+		// Host params are whole labels, e.g. "{tenant}.example.com".
+		labels := strings.Split(u.Host, ".")
+		for i, label := range labels {
+			if strings.HasPrefix(label, "{") {
+				labels[i] = goutil.String(b.params[label])
+			}
 		}
+		u.Host = strings.Join(labels, ".")
 	}
+	u.Path = b.replaceVars(path)
+	return u
+}
 
-	for paramRegex, name := range varParams {
-		path = strings.NewReplacer(paramRegex, goutil.String(b.params[name])).Replace(path)
+// replaceVars substitutes {name} and {name:regex} placeholders in tpl
+// with the builder params. Placeholders may share a segment, as in
+// "{name}.{ext}", and regexes may contain braces.
+func (b *BuildRequestURL) replaceVars(path string) string {
+	if strings.IndexByte(path, '{') == -1 {
+		return path
 	}
 
-	u.Path = path
-
-	return u
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		end := -1
+		if path[i] == '{' {
+			end = closingBrace(path, i)
+		}
+		if end == -1 {
+			sb.WriteByte(path[i])
+			continue
+		}
+		name, _ := splitParamDef(path[i+1 : end])
+		sb.WriteString(goutil.String(b.params["{"+name+"}"]))
+		i = end
+	}
+	return sb.String()
 }
@@ -62,7 +62,7 @@ func TestRoute_Handler_NilOnEmptyChain(t *testing.T) {
 
 func TestValidateMethods_PanicOnUnknown(t *testing.T) {
 	defer func() { assert.NotNil(t, recover()) }()
-	validateMethods([]string{"BOGUS"})
+	validateMethods(stdMethods, []string{"BOGUS"})
 }
 
 // =========================================================================
@@ -0,0 +1,309 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RouteGroup is a set of routes sharing a path prefix, middlewares, host
+// and route name prefix. Unlike the closure form Router.Group, a
+// RouteGroup is a value: it can be stored, passed to other packages and
+// used from several goroutines during setup (see Controller for the
+// exception).
+//
+//	api := r.NewGroup("/api", authMiddleware)
+//	api.GET("/users", listUsers)
+//	v1 := api.Group("/v1").Name("v1.")
+//	v1.GET("/ping", ping) // GET /api/v1/ping, middlewares: auth
+//
+// Group middlewares are resolved when a route is added, so Use only
+// affects routes added to the group (or its nested groups) afterwards.
+type RouteGroup struct {
+	router *Router
+	parent *RouteGroup
+
+	// prefix is the full path prefix, including parent prefixes.
+	prefix string
+	// host is the host pattern routes are bound to ("" = any host).
+	host string
+	// namePrefix is prepended to the name of named routes.
+	namePrefix string
+	// handlers are the group's own middlewares (parents' excluded).
+	handlers HandlersChain
+
+	// conds are the request conditions of the group's routes, see When.
+	conds []Condition
+	// variant is the route variant of a Variant group.
+	variant *routeVariant
+	// version is the API version of a group returned by
+	// APIVersions.Version and its nested groups.
+	version *apiVersion
+	// meta is the group's own route metadata (parents' excluded), see With.
+	meta map[string]any
+
+	noRoute HandlersChain
+	// hooks are the group's own error and panic handlers.
+	hooks hookSet
+}
+
+// NewGroup returns a RouteGroup for prefix. Middlewares supplied as
+// middles run before route-level middlewares. Called inside a closure
+// Group or Host, the new group is nested in it.
+func (r *Router) NewGroup(prefix string, middles ...HandlerFunc) *RouteGroup {
+	return r.group().Group(prefix, middles...)
+}
+
+// group returns the group routes registered on r are added to: the group
+// of the enclosing closure Group / Host, or the root group.
+func (r *Router) group() *RouteGroup {
+	if r.curGroup != nil {
+		return r.curGroup
+	}
+	return &RouteGroup{router: r}
+}
+
+// withGroup runs fn with g as the group for routes registered on r.
+func (r *Router) withGroup(g *RouteGroup, fn func()) {
+	prev := r.curGroup
+	r.curGroup = g
+	defer func() { r.curGroup = prev }()
+	fn()
+}
+
+// Router returns the router the group registers routes on.
+func (g *RouteGroup) Router() *Router { return g.router }
+
+// Prefix returns the full path prefix of the group.
+func (g *RouteGroup) Prefix() string { return g.prefix }
+
+// Group returns a nested group: prefixes concatenate, middlewares stack,
+// and host and name prefix are inherited.
+func (g *RouteGroup) Group(prefix string, middles ...HandlerFunc) *RouteGroup {
+	ng := &RouteGroup{
+		router:     g.router,
+		parent:     g,
+		prefix:     g.prefix,
+		host:       g.host,
+		namePrefix: g.namePrefix,
+		conds:      g.conds,
+		version:    g.version,
+		variant:    g.variant,
+		handlers:   middles,
+	}
+	if prefix != "" {
+		ng.prefix = g.prefix + g.router.formatPath(prefix)
+	}
+	return ng
+}
+
+// Host returns a nested group whose routes only match requests whose Host
+// header matches pattern. See Router.Host.
+func (g *RouteGroup) Host(pattern string, middles ...HandlerFunc) *RouteGroup {
+	ng := g.Group("", middles...)
+	r := g.router
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ng.host = r.hostRoutes(pattern).pattern
+	return ng
+}
+
+// Name sets the prefix prepended to the name of routes added to the group,
+// appended to the name prefix inherited from the parent group. It panics
+// once the router is frozen.
+func (g *RouteGroup) Name(prefix string) *RouteGroup {
+	g.router.lockUnfrozen("rux: cannot set group name prefix after router is frozen")
+	defer g.router.mu.Unlock()
+	parentPrefix := ""
+	if g.parent != nil {
+		parentPrefix = g.parent.namePrefix
+	}
+	g.namePrefix = parentPrefix + prefix
+	return g
+}
+
+// Use appends group middlewares. They apply to routes added afterwards.
+func (g *RouteGroup) Use(middles ...HandlerFunc) *RouteGroup {
+	g.router.lockUnfrozen("rux: cannot Use after router is frozen")
+	g.handlers = append(g.handlers, middles...)
+	g.router.mu.Unlock()
+	return g
+}
+
+// Handlers returns the full middleware chain of the group: parent group
+// middlewares first.
+func (g *RouteGroup) Handlers() HandlersChain {
+	g.router.mu.Lock()
+	defer g.router.mu.Unlock()
+	return g.chain()
+}
+
+// chain builds the group middleware chain. Callers hold router.mu.
+func (g *RouteGroup) chain() HandlersChain {
+	if g.parent == nil {
+		return g.handlers
+	}
+	parent := g.parent.chain()
+	if len(parent) == 0 {
+		return g.handlers
+	}
+	if len(g.handlers) == 0 {
+		return parent
+	}
+	// Create a fresh slice to avoid aliasing the parent's backing array.
+	return append(append(HandlersChain{}, parent...), g.handlers...)
+}
+
+// NotFound sets the 404 handlers chain for requests below the group
+// prefix that match no route. The group with the longest matching prefix
+// wins. Only static prefixes (without params) are supported.
+func (g *RouteGroup) NotFound(handlers ...HandlerFunc) {
+	r := g.router
+	r.lockUnfrozen("rux: cannot set group NotFound after router is frozen")
+	defer r.mu.Unlock()
+
+	if g.noRoute == nil {
+		// Keep the list ordered by prefix length, longest first.
+		i := 0
+		for i < len(r.groupNoRoutes) && len(r.groupNoRoutes[i].prefix) >= len(g.prefix) {
+			i++
+		}
+		r.groupNoRoutes = append(r.groupNoRoutes, nil)
+		copy(r.groupNoRoutes[i+1:], r.groupNoRoutes[i:])
+		r.groupNoRoutes[i] = g
+	}
+	g.noRoute = handlers
+}
+
+// matchNotFound reports whether the group NotFound applies to path on the
+// matched host table.
+func (g *RouteGroup) matchNotFound(host *hostRoutes, path string) bool {
+	if g.host != "" && (host == nil || host.pattern != g.host) {
+		return false
+	}
+	if g.prefix == "" {
+		return true
+	}
+	if !strings.HasPrefix(path, g.prefix) {
+		return false
+	}
+	return len(path) == len(g.prefix) || path[len(g.prefix)] == '/'
+}
+
+/*************************************************************
+ * Route registration
+ *************************************************************/
+
+// Add registers a route on the given methods (defaults to GET if methods empty).
+func (g *RouteGroup) Add(path string, handler HandlerFunc, methods ...string) *Route {
+	return g.AddRoute(newRoute(path, handler, methods))
+}
+
+// AddNamed is like Add with a route name. The group name prefix is
+// prepended to name.
+func (g *RouteGroup) AddNamed(name, path string, handler HandlerFunc, methods ...string) *Route {
+	return g.AddRoute(newNamedRoute(name, path, handler, methods))
+}
+
+// AddRoute registers a pre-constructed Route in the group.
+func (g *RouteGroup) AddRoute(route *Route) *Route {
+	g.router.appendRoute(route, g)
+	return route
+}
+
+// Verb shortcuts.
+
+func (g *RouteGroup) GET(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, GET).Use(mw...)
+}
+func (g *RouteGroup) HEAD(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, HEAD).Use(mw...)
+}
+func (g *RouteGroup) POST(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, POST).Use(mw...)
+}
+func (g *RouteGroup) PUT(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, PUT).Use(mw...)
+}
+func (g *RouteGroup) PATCH(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, PATCH).Use(mw...)
+}
+func (g *RouteGroup) DELETE(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, DELETE).Use(mw...)
+}
+func (g *RouteGroup) OPTIONS(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, OPTIONS).Use(mw...)
+}
+func (g *RouteGroup) CONNECT(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, CONNECT).Use(mw...)
+}
+func (g *RouteGroup) TRACE(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, TRACE).Use(mw...)
+}
+
+// Any registers a route on every supported HTTP method.
+func (g *RouteGroup) Any(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
+	return g.Add(path, h, g.router.AnyMethods()...).Use(mw...)
+}
+
+/*************************************************************
+ * Controller / Resource
+ *************************************************************/
+
+// Controller registers the routes of controller c under basePath in a
+// nested group, with shared middlewares. c is a GroupController, given
+// the group, or a ControllerFace, whose AddRoutes registers on the router
+// as inside a closure Group; only the former may run concurrently with
+// other registrations.
+func (g *RouteGroup) Controller(basePath string, c any, middles ...HandlerFunc) {
+	grp := g.Group(basePath, middles...)
+	switch c := c.(type) {
+	case GroupController:
+		c.AddGroupRoutes(grp)
+	case ControllerFace:
+		g.router.withGroup(grp, func() { c.AddRoutes(g.router) })
+	default:
+		panic(fmt.Sprintf("rux: controller %T must implement GroupController or ControllerFace", c))
+	}
+}
+
+// Resource registers RESTful routes for the given controller struct in a
+// nested group. See Router.Resource.
+func (g *RouteGroup) Resource(basePath string, controller any, middles ...HandlerFunc) {
+	g.ResourceWith(basePath, controller, ResourceOptions{Middlewares: middles})
+}
+
+/*************************************************************
+ * Static file helpers
+ *************************************************************/
+
+// StaticFile registers a single static file under the given path.
+func (g *RouteGroup) StaticFile(path, filePath string) *Route {
+	return g.GET(path, func(c *Context) { c.File(filePath) })
+}
+
+// StaticDir serves files from rootDir under prefixURL using http.FileServer.
+func (g *RouteGroup) StaticDir(prefixURL, rootDir string) *Route {
+	return g.StaticFS(prefixURL, http.Dir(rootDir))
+}
+
+// StaticFS serves files from the given http.FileSystem under prefixURL.
+// The matched prefix (including the group prefix) is stripped the same
+// way as for Mount.
+func (g *RouteGroup) StaticFS(prefixURL string, fs http.FileSystem) *Route {
+	handler := http.FileServer(fs)
+	return g.GET(prefixURL+"/*file", func(c *Context) {
+		handler.ServeHTTP(c.Resp, stripPrefixRequest(c, "file"))
+	})
+}
+
+// StaticFiles serves files from rootDir under prefixURL. The exts argument
+// is reserved for future extension filtering and is currently ignored.
+func (g *RouteGroup) StaticFiles(prefixURL, rootDir, exts string) *Route {
+	fs := http.FileServer(http.Dir(rootDir))
+	_ = exts // reserved for future extension filtering
+	return g.GET(fmt.Sprintf("%s/*file", prefixURL), func(c *Context) {
+		c.Req.URL.Path = c.Param("file")
+		fs.ServeHTTP(c.Resp, c.Req)
+	})
+}
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestRouteGroup_PrefixAndMiddlewares(t *testing.T) {
+	var order []string
+	mw := func(name string) HandlerFunc {
+		return func(c *Context) { order = append(order, name); c.Next() }
+	}
+
+	r := New()
+	api := r.NewGroup("/api", mw("api"))
+	v1 := api.Group("/v1/", mw("v1"))
+	v1.GET("/users", textHandler("users"), mw("route"))
+	api.POST("/ping", textHandler("pong"))
+
+	assert.Eq(t, "/api", api.Prefix())
+	assert.Eq(t, "/api/v1", v1.Prefix())
+	assert.Same(t, r, v1.Router())
+
+	w := serve(r, GET, "/api/v1/users")
+	assert.Eq(t, 200, w.Code)
+	assert.Eq(t, "users", w.Body.String())
+	assert.Eq(t, []string{"api", "v1", "route"}, order)
+
+	w = serve(r, POST, "/api/ping")
+	assert.Eq(t, "pong", w.Body.String())
+}
+
+func TestRouteGroup_UseAppliesToLaterRoutes(t *testing.T) {
+	var calls []string
+	r := New()
+	g := r.NewGroup("/g")
+	g.GET("/before", textHandler("before"))
+	nested := g.Group("/n")
+	g.Use(func(c *Context) { calls = append(calls, c.Req.URL.Path); c.Next() })
+	g.GET("/after", textHandler("after"))
+	nested.GET("/x", textHandler("x"))
+
+	assert.Len(t, g.Handlers(), 1)
+	assert.Len(t, nested.Handlers(), 1)
+
+	serve(r, GET, "/g/before")
+	serve(r, GET, "/g/after")
+	serve(r, GET, "/g/n/x")
+	assert.Eq(t, []string{"/g/after", "/g/n/x"}, calls)
+}
+
+func TestRouteGroup_NamePrefix(t *testing.T) {
+	r := New()
+	api := r.NewGroup("/api").Name("api.")
+	api.AddNamed("status", "/status", textHandler("ok"), GET)
+	v1 := api.Group("/v1").Name("v1.")
+	v1.AddNamed("users", "/users", textHandler("users"), GET)
+	api.Resource("/", &fakeResource{})
+
+	assert.NotNil(t, r.GetRoute("api.status"))
+	assert.NotNil(t, r.GetRoute("api.v1.users"))
+	assert.NotNil(t, r.GetRoute("api.fakeresource_show"))
+	assert.Nil(t, r.GetRoute("status"))
+	assert.Eq(t, "/api/v1/users", r.GetRoute("api.v1.users").Path())
+}
+
+func TestRouteGroup_ControllerAndStatic(t *testing.T) {
+	r := New()
+	g := r.NewGroup("/admin")
+	g.Controller("/ctl", &fakeController{})
+	g.StaticFile("/robots.txt", "testdata/robots.txt")
+
+	_, ok := r.staticRoutes[methodIndex(POST)]["/admin/ctl"]
+	assert.True(t, ok)
+	_, ok = r.staticRoutes[methodIndex(GET)]["/admin/robots.txt"]
+	assert.True(t, ok)
+
+	// The closure group is restored after Controller returns.
+	r.GET("/top", textHandler("top"))
+	_, ok = r.staticRoutes[methodIndex(GET)]["/top"]
+	assert.True(t, ok)
+
+	g.Controller("/grp", &fakeGroupController{})
+	_, ok = r.staticRoutes[methodIndex(GET)]["/admin/grp/list"]
+	assert.True(t, ok)
+	assert.PanicsMsg(t, func() { g.Controller("/x", struct{}{}) },
+		"rux: controller struct {} must implement GroupController or ControllerFace")
+}
+
+type fakeGroupController struct{}
+
+func (*fakeGroupController) AddGroupRoutes(g *RouteGroup) {
+	g.GET("/list", textHandler("list"))
+}
+
+// GroupController registrations leave the router state alone, so they can
+// run next to other registrations.
+func TestRouteGroup_GroupControllerConcurrent(t *testing.T) {
+	r := New()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.NewGroup("/g"+strconv.Itoa(i)).Controller("/c", &fakeGroupController{})
+			r.GET("/top"+strconv.Itoa(i), textHandler("top"))
+		}()
+	}
+	wg.Wait()
+
+	for i := range 8 {
+		n := strconv.Itoa(i)
+		assert.Eq(t, "list", serve(r, GET, "/g"+n+"/c/list").Body.String())
+		assert.Eq(t, "top", serve(r, GET, "/top"+n).Body.String())
+	}
+}
+
+func TestRouteGroup_NotFound(t *testing.T) {
+	r := New()
+	r.NotFound(textHandler("global 404"))
+	api := r.NewGroup("/api")
+	api.GET("/users", textHandler("users"))
+	api.NotFound(textHandler("api 404"))
+	v2 := api.Group("/v2")
+	v2.NotFound(textHandler("v2 404"))
+
+	assert.Eq(t, "api 404", serve(r, GET, "/api/nope").Body.String())
+	assert.Eq(t, "api 404", serve(r, GET, "/api").Body.String())
+	assert.Eq(t, "v2 404", serve(r, GET, "/api/v2/nope").Body.String())
+	assert.Eq(t, "global 404", serve(r, GET, "/apix").Body.String())
+	assert.Eq(t, "global 404", serve(r, GET, "/other").Body.String())
+	assert.Eq(t, "users", serve(r, GET, "/api/users").Body.String())
+}
+
+func TestRouteGroup_Host(t *testing.T) {
+	r := New()
+	g := r.NewGroup("/v1").Host("{tenant}.example.com")
+	g.GET("/whoami", func(c *Context) { c.Text(200, c.Param("tenant")) })
+	g.NotFound(textHandler("tenant 404"))
+
+	assert.Eq(t, "acme", serve(r, GET, "/v1/whoami", "Host", "acme.example.com").Body.String())
+	assert.Eq(t, 404, serve(r, GET, "/v1/whoami", "Host", "other.org").Code)
+	assert.Eq(t, "tenant 404", serve(r, GET, "/v1/nope", "Host", "acme.example.com").Body.String())
+	assert.NotEq(t, "tenant 404", serve(r, GET, "/v1/nope", "Host", "other.org").Body.String())
+}
+
+func TestRouter_GroupClosureWrapsNewGroup(t *testing.T) {
+	r := New()
+	r.Group("/api", func() {
+		g := r.NewGroup("/inner")
+		g.GET("/x", textHandler("x"))
+		r.GET("/y", textHandler("y"))
+	})
+	r.GET("/z", textHandler("z"))
+
+	assert.Eq(t, "x", serve(r, GET, "/api/inner/x").Body.String())
+	assert.Eq(t, "y", serve(r, GET, "/api/y").Body.String())
+	assert.Eq(t, "z", serve(r, GET, "/z").Body.String())
+}
+
+func TestRouteGroup_ConcurrentRegistration(t *testing.T) {
+	r := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			g := r.NewGroup(fmt.Sprintf("/g%d", i))
+			for j := 0; j < 20; j++ {
+				g.GET(fmt.Sprintf("/r%d", j), textHandler("ok"))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, r.Routes(), 160)
+	assert.Eq(t, 200, serve(r, GET, "/g7/r19").Code)
+}
+
+func TestRouteGroup_FrozenPanics(t *testing.T) {
+	r := New()
+	g := r.NewGroup("/g")
+	r.Freeze()
+	assert.Panics(t, func() { g.GET("/x", textHandler("x")) })
+	assert.Panics(t, func() { g.Use(textHandler("mw")) })
+	assert.Panics(t, func() { g.NotFound(textHandler("404")) })
+	assert.PanicsMsg(t, func() { g.Name("g.") }, "rux: cannot set group name prefix after router is frozen")
+}
+
+func TestRouteGroup_RegistrationDuringFreeze(t *testing.T) {
+	r := New()
+	r.Use(func(c *Context) { c.SetHeader("X-Global", "on"); c.Next() })
+	var wg, started sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		started.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Registrations racing Freeze either finish before it or panic.
+			defer func() {
+				if rec := recover(); rec != nil {
+					assert.StrContains(t, fmt.Sprint(rec), "after router is frozen")
+				}
+			}()
+			g := r.NewGroup(fmt.Sprintf("/g%d", i)).Name(fmt.Sprintf("g%d.", i))
+			g.GET("/r", textHandler("ok"))
+			started.Done()
+			for j := 0; j < 200; j++ {
+				g.AddNamed(fmt.Sprintf("r%d", j), fmt.Sprintf("/r%d", j), textHandler("ok"))
+			}
+		}(i)
+	}
+	started.Wait()
+	r.Freeze()
+	wg.Wait()
+
+	for _, route := range r.Routes() {
+		w := serve(r, GET, route.Path)
+		assert.Eq(t, 200, w.Code, route.Path)
+		assert.Eq(t, "on", w.Header().Get("X-Global"), route.Path)
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import rdebug "runtime/debug"
+
+// Kinds of error hooks, indexes into hookSet.
+const (
+	errorHook = iota
+	panicHook
+)
+
+// hookSet holds the error and panic handlers of a route or group.
+type hookSet [2]HandlerFunc
+
+// OnError sets the error handler of the route. It overrides the group
+// and Router.OnError handlers for requests matching the route.
+func (r *Route) OnError(h HandlerFunc) *Route { return r.setHook(errorHook, h) }
+
+// OnPanic sets the panic handler of the route. It overrides the group
+// and Router.OnPanic handlers for requests matching the route.
+func (r *Route) OnPanic(h HandlerFunc) *Route { return r.setHook(panicHook, h) }
+
+func (r *Route) setHook(kind int, h HandlerFunc) *Route {
+	if r.group != nil && r.group.router.frozen.Load() {
+		panic("rux: cannot set route error handlers after router is frozen")
+	}
+	r.hooks[kind] = h
+	for _, exp := range r.expansions {
+		exp.hooks[kind] = h
+	}
+	return r
+}
+
+// OnError sets the error handler of the group. It overrides the handlers
+// of parent groups and Router.OnError for the group's routes and its
+// NotFound chain, including routes registered before the call.
+//
+//	api := r.NewGroup("/api").OnError(jsonErrors)
+//	admin := r.NewGroup("/admin").OnError(htmlErrorPage)
+func (g *RouteGroup) OnError(h HandlerFunc) *RouteGroup { return g.setHook(errorHook, h) }
+
+// OnPanic sets the panic handler of the group, like OnError.
+func (g *RouteGroup) OnPanic(h HandlerFunc) *RouteGroup { return g.setHook(panicHook, h) }
+
+func (g *RouteGroup) setHook(kind int, h HandlerFunc) *RouteGroup {
+	g.router.lockUnfrozen("rux: cannot set group error handlers after router is frozen")
+	g.hooks[kind] = h
+	g.router.mu.Unlock()
+	return g
+}
+
+// Recovered returns the recovered panic value while a panic handler runs,
+// or nil.
+func (c *Context) Recovered() any { return c.recovered }
+
+// PanicStack returns the stack trace of the recovered panic while a panic
+// handler runs, or nil.
+func (c *Context) PanicStack() []byte { return c.panicStack }
+
+// resolveHook returns the most specific handler of kind for a request
+// that matched route, or the NotFound chain of group g: the route's, then
+// the groups' (innermost first), the router's, and, for a mounted router,
+// the same lookup on the mounting request. nil if none is set.
+func (c *Context) resolveHook(kind int, route *Route, g *RouteGroup) HandlerFunc {
+	for {
+		if route != nil {
+			if h := route.hooks[kind]; h != nil {
+				return h
+			}
+			g = route.group
+		}
+		for ; g != nil; g = g.parent {
+			if h := g.hooks[kind]; h != nil {
+				return h
+			}
+		}
+
+		h := c.router.OnError
+		if kind == panicHook {
+			h = c.router.OnPanic
+		}
+		if h != nil || c.parent == nil {
+			return h
+		}
+		c = c.parent
+		route, g = c.matchedRoute, nil
+	}
+}
+
+// recoverPanic runs the panic handler resolved for route / g with the
+// recovered value rec, or panics again if there is none.
+func (c *Context) recoverPanic(rec any, route *Route, g *RouteGroup) {
+	h := c.resolveHook(panicHook, route, g)
+	if h == nil {
+		panic(rec)
+	}
+	c.recovered = rec
+	c.panicStack = rdebug.Stack()
+	c.Set(CTXRecoverResult, rec)
+	h(c)
+}
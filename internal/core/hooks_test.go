@@ -0,0 +1,103 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func namedHook(name string) HandlerFunc {
+	return func(c *Context) {
+		msg := name
+		if rec := c.Recovered(); rec != nil {
+			msg += " panic: " + rec.(string)
+		} else if err := c.FirstError(); err != nil {
+			msg += " error: " + err.Error()
+		}
+		c.Text(500, msg)
+	}
+}
+
+func failWith(msg string) HandlerFunc {
+	return func(c *Context) { c.Fail(errors.New(msg)) }
+}
+
+func panicWith(msg string) HandlerFunc {
+	return func(c *Context) { panic(msg) }
+}
+
+func TestRouter_ErrorHooks_MostSpecificWins(t *testing.T) {
+	r := New()
+	r.OnError = namedHook("router")
+	r.OnPanic = namedHook("router")
+	r.GET("/root", failWith("e1"))
+
+	api := r.NewGroup("/api").OnError(namedHook("api")).OnPanic(namedHook("api"))
+	api.GET("/fail", failWith("e2"))
+	api.GET("/panic", panicWith("p1"))
+	// Set after the route is registered, still applies.
+	v1 := api.Group("/v1")
+	v1.GET("/fail", failWith("e3"))
+	v1.OnError(namedHook("v1"))
+	v1.GET("/own", failWith("e4")).OnError(namedHook("route"))
+	v1.GET("/opt[/{id}]", panicWith("p2")).OnPanic(namedHook("optional"))
+	api.NotFound(failWith("missing"))
+
+	assert.Eq(t, "router error: e1", serve(r, GET, "/root").Body.String())
+	assert.Eq(t, "api error: e2", serve(r, GET, "/api/fail").Body.String())
+	assert.Eq(t, "api panic: p1", serve(r, GET, "/api/panic").Body.String())
+	assert.Eq(t, "v1 error: e3", serve(r, GET, "/api/v1/fail").Body.String())
+	assert.Eq(t, "route error: e4", serve(r, GET, "/api/v1/own").Body.String())
+	assert.Eq(t, "optional panic: p2", serve(r, GET, "/api/v1/opt/1").Body.String())
+	assert.Eq(t, "api error: missing", serve(r, GET, "/api/nope").Body.String())
+}
+
+func TestRouter_PanicHook_Accessors(t *testing.T) {
+	var stack []byte
+	var legacy any
+	r := New()
+	r.GET("/boom", panicWith("boom")).OnPanic(func(c *Context) {
+		stack = c.PanicStack()
+		legacy, _ = c.Get(CTXRecoverResult)
+		c.Text(500, c.Recovered().(string))
+	})
+	r.GET("/unhandled", panicWith("unhandled"))
+
+	w := serve(r, GET, "/boom")
+	assert.Eq(t, 500, w.Code)
+	assert.Eq(t, "boom", w.Body.String())
+	assert.StrContains(t, string(stack), "panicWith")
+	assert.Eq(t, "boom", legacy)
+
+	// Without any panic handler the panic propagates.
+	assert.Panics(t, func() { serve(r, GET, "/unhandled") })
+}
+
+func TestRouter_ErrorHooks_Mount(t *testing.T) {
+	sub := New()
+	sub.GET("/fail", failWith("sub"))
+	sub.GET("/panic", panicWith("sub"))
+	own := New()
+	own.OnError = namedHook("own")
+	own.GET("/fail", failWith("own"))
+
+	r := New()
+	r.OnError = namedHook("router")
+	r.Mount("/admin/sub", sub).OnPanic(namedHook("admin"))
+	r.Mount("/own", own)
+
+	assert.Eq(t, "router error: sub", serve(r, GET, "/admin/sub/fail").Body.String())
+	assert.Eq(t, "admin panic: sub", serve(r, GET, "/admin/sub/panic").Body.String())
+	assert.Eq(t, "own error: own", serve(r, GET, "/own/fail").Body.String())
+}
+
+func TestRouter_ErrorHooks_FrozenPanics(t *testing.T) {
+	r := New()
+	g := r.NewGroup("/g")
+	route := g.GET("/x", textHandler("x"))
+	r.Freeze()
+
+	assert.Panics(t, func() { g.OnError(namedHook("g")) })
+	assert.Panics(t, func() { route.OnPanic(namedHook("route")) })
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// hostLabelKind classifies one dot-separated label of a host pattern.
+type hostLabelKind uint8
+
+const (
+	hostLiteral  hostLabelKind = iota // "api"
+	hostParam                         // "{tenant}" — exactly one label
+	hostWildcard                      // "*" — one or more labels, leftmost only
+)
+
+// hostLabel is a parsed host pattern label.
+type hostLabel struct {
+	kind  hostLabelKind
+	value string // literal text, or param name
+}
+
+// hostRoutes is the route table of one host pattern.
+//
+// Host patterns are dot-separated labels:
+//
+//	api.example.com            exact host
+//	{tenant}.api.example.com   one label bound to the "tenant" param
+//	*.example.com              any subdomain (one or more labels)
+type hostRoutes struct {
+	routeTable
+
+	pattern string
+	// labels is nil for exact (literal-only) patterns.
+	labels []hostLabel
+	// params is the number of host params bound on match.
+	params int
+
+	noRoute HandlersChain
+}
+
+// clone returns a copy of h with a deep copy of its route table.
+func (h *hostRoutes) clone() *hostRoutes {
+	c := *h
+	c.routeTable = h.routeTable.clone()
+	return &c
+}
+
+// parseHostPattern parses and validates a host pattern.
+func parseHostPattern(pattern string) *hostRoutes {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		panic("rux: empty host pattern")
+	}
+
+	h := &hostRoutes{pattern: pattern}
+	parts := strings.Split(pattern, ".")
+	if strings.IndexByte(pattern, '{') == -1 && strings.IndexByte(pattern, '*') == -1 {
+		for _, part := range parts {
+			if part == "" {
+				panic(fmt.Sprintf("rux: invalid host pattern %q", pattern))
+			}
+		}
+		return h
+	}
+
+	h.labels = make([]hostLabel, len(parts))
+	for i, part := range parts {
+		switch {
+		case part == "*":
+			if i != 0 {
+				panic(fmt.Sprintf("rux: host wildcard must be the leftmost label: %q", pattern))
+			}
+			h.labels[i] = hostLabel{kind: hostWildcard}
+		case len(part) > 2 && part[0] == '{' && part[len(part)-1] == '}':
+			h.labels[i] = hostLabel{kind: hostParam, value: part[1 : len(part)-1]}
+			h.params++
+		case part == "" || strings.ContainsAny(part, "{}*"):
+			panic(fmt.Sprintf("rux: invalid host pattern label %q in %q", part, pattern))
+		default:
+			h.labels[i] = hostLabel{kind: hostLiteral, value: part}
+		}
+	}
+	return h
+}
+
+// matchHost reports whether host (lowercase, no port) matches the pattern,
+// appending host params to ps on success.
+func (h *hostRoutes) matchHost(host string, ps *Params) bool {
+	snap := ps.n
+	// Match right to left so the leftmost wildcard can absorb the rest.
+	for i := len(h.labels) - 1; i >= 0; i-- {
+		l := h.labels[i]
+		if l.kind == hostWildcard {
+			if host != "" {
+				return true
+			}
+			break
+		}
+
+		label, rest, ok := cutLastLabel(host)
+		if !ok || (l.kind == hostLiteral && label != l.value) {
+			break
+		}
+		if l.kind == hostParam {
+			ps.append(l.value, label)
+		}
+		host = rest
+		if i == 0 && host == "" {
+			return true
+		}
+	}
+	ps.n = snap
+	return false
+}
+
+// cutLastLabel splits "a.b.c" into "c" and "a.b". ok is false when the
+// last label is empty (e.g. "", "a." or "a..b").
+func cutLastLabel(host string) (label, rest string, ok bool) {
+	dot := strings.LastIndexByte(host, '.')
+	if dot < 0 {
+		return host, "", host != ""
+	}
+	label, rest = host[dot+1:], host[:dot]
+	return label, rest, label != "" && rest != ""
+}
+
+// Host registers all routes added inside fn for requests whose Host header
+// matches pattern. Like Group, middles run before route-level middlewares.
+// Host params (e.g. "{tenant}.example.com") are read via Context.Param.
+//
+// Host-bound routes are tried before host-less ones; a request whose host
+// matches no pattern only sees host-less routes.
+func (r *Router) Host(pattern string, fn func(), middles ...HandlerFunc) {
+	r.withGroup(r.group().Host(pattern, middles...), fn)
+}
+
+// HostNotFound sets the 404 handlers chain for requests whose host matches
+// pattern but whose path matches no route.
+func (r *Router) HostNotFound(pattern string, handlers ...HandlerFunc) {
+	r.hostRoutes(pattern).noRoute = handlers
+}
+
+// hostRoutes returns the table for pattern, creating it on first use.
+func (r *Router) hostRoutes(pattern string) *hostRoutes {
+	h := parseHostPattern(pattern)
+	if existing := r.lookupHostPattern(h.pattern); existing != nil {
+		return existing
+	}
+	if r.frozen.Load() {
+		panic("rux: cannot add host after router is frozen")
+	}
+	if h.labels == nil {
+		if r.exactHosts == nil {
+			r.exactHosts = make(map[string]*hostRoutes, 2)
+		}
+		r.exactHosts[h.pattern] = h
+	} else {
+		r.patternHosts = append(r.patternHosts, h)
+	}
+	r.hasHosts = true
+	return h
+}
+
+// lookupHostPattern returns the registered table for a normalized pattern.
+func (r *Router) lookupHostPattern(pattern string) *hostRoutes {
+	if h, ok := r.exactHosts[pattern]; ok {
+		return h
+	}
+	for _, h := range r.patternHosts {
+		if h.pattern == pattern {
+			return h
+		}
+	}
+	return nil
+}
+
+// matchHost resolves the host table for a request Host header. Exact hosts
+// win (with the port, then without), followed by patterns in registration
+// order. Returns nil if none match.
+func (r *Router) matchHost(host string, ps *Params) *hostRoutes {
+	host = strings.ToLower(host)
+	if h, ok := r.exactHosts[host]; ok {
+		return h
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+		if h, ok := r.exactHosts[host]; ok {
+			return h
+		}
+	}
+	for _, h := range r.patternHosts {
+		if h.matchHost(host, ps) {
+			return h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestParseHostPattern(t *testing.T) {
+	h := parseHostPattern("API.Example.com")
+	assert.Eq(t, "api.example.com", h.pattern)
+	assert.Nil(t, h.labels)
+
+	h = parseHostPattern("{tenant}.api.example.com")
+	assert.Eq(t, 4, len(h.labels))
+	assert.Eq(t, 1, h.params)
+	assert.Eq(t, hostParam, h.labels[0].kind)
+
+	assert.Panics(t, func() { parseHostPattern("") })
+	assert.Panics(t, func() { parseHostPattern("api.*.com") })
+	assert.Panics(t, func() { parseHostPattern("a..com") })
+	assert.Panics(t, func() { parseHostPattern("x{a}.com") })
+}
+
+func TestHostRoutes_MatchHost(t *testing.T) {
+	cases := []struct {
+		pattern, host string
+		want          bool
+	}{
+		{"{t}.example.com", "acme.example.com", true},
+		{"{t}.example.com", "example.com", false},
+		{"{t}.example.com", "a.b.example.com", false},
+		{"{t}.example.com", ".example.com", false},
+		{"*.example.com", "a.b.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"{a}.{b}.com", "x.y.com", true},
+	}
+	for _, c := range cases {
+		var ps Params
+		got := parseHostPattern(c.pattern).matchHost(c.host, &ps)
+		assert.Eq(t, c.want, got, "pattern=%q host=%q", c.pattern, c.host)
+		if !got {
+			assert.Eq(t, 0, ps.Len())
+		}
+	}
+}
+
+func TestRouter_Host_Dispatch(t *testing.T) {
+	r := New()
+	r.Host("api.example.com", func() {
+		r.GET("/users", textHandler("exact"))
+	})
+	r.Host("{tenant}.example.com", func() {
+		r.GET("/users/{id}", func(c *Context) {
+			c.Text(200, c.Param("tenant")+":"+c.Param("id"))
+		})
+	})
+	r.Host("*.static.example.com", func() {
+		r.GET("/users", textHandler("wild"))
+	})
+	r.GET("/users", textHandler("any"))
+
+	assert.Eq(t, "exact", serve(r, "GET", "/users", "Host", "api.example.com").Body.String())
+	assert.Eq(t, "exact", serve(r, "GET", "/users", "Host", "API.example.com:8080").Body.String())
+	assert.Eq(t, "acme:7", serve(r, "GET", "/users/7", "Host", "acme.example.com").Body.String())
+	assert.Eq(t, "wild", serve(r, "GET", "/users", "Host", "cdn.eu.static.example.com").Body.String())
+	// Host-less routes serve any host, including matched hosts on a miss.
+	assert.Eq(t, "any", serve(r, "GET", "/users", "Host", "other.org").Body.String())
+	assert.Eq(t, "any", serve(r, "GET", "/users", "Host", "acme.example.com").Body.String())
+	assert.Eq(t, 404, serve(r, "GET", "/users/7", "Host", "other.org").Code)
+	// HEAD mirror applies to host tables.
+	assert.Eq(t, 200, serve(r, "HEAD", "/users/7", "Host", "acme.example.com").Code)
+}
+
+func TestRouter_HostNotFound(t *testing.T) {
+	r := New()
+	r.Host("{tenant}.example.com", func() {
+		r.GET("/x", textHandler("x"))
+	})
+	r.HostNotFound("{tenant}.example.com", func(c *Context) {
+		c.Text(404, "no such page for "+c.Param("tenant"))
+	})
+
+	w := serve(r, "GET", "/missing", "Host", "acme.example.com")
+	assert.Eq(t, 404, w.Code)
+	assert.Eq(t, "no such page for acme", w.Body.String())
+
+	w = serve(r, "GET", "/missing", "Host", "example.org")
+	assert.Eq(t, 404, w.Code)
+	assert.NotEq(t, "no such page for ", w.Body.String())
+}
+
+func TestRouter_Host_MethodNotAllowed(t *testing.T) {
+	r := New(HandleMethodNotAllowed)
+	r.Host("api.example.com", func() {
+		r.POST("/items", textHandler("post"))
+	})
+	r.GET("/items", textHandler("get"))
+
+	w := serve(r, "DELETE", "/items", "Host", "api.example.com")
+	assert.Eq(t, 405, w.Code)
+	assert.Eq(t, "GET, HEAD, POST", w.Header().Get("Allow"))
+}
+
+func TestRouter_Host_RouteInfoAndBuildURL(t *testing.T) {
+	r := New()
+	r.Host("{tenant}.example.com", func() {
+		r.AddNamed("user", "/users/{id}", textHandler("u"), GET)
+	}, func(c *Context) { c.Next() })
+	r.Host("*.example.org", func() {
+		r.AddNamed("wild", "/w", textHandler("w"), GET)
+	})
+
+	route := r.GetRoute("user")
+	assert.Eq(t, "{tenant}.example.com", route.Host())
+	assert.Eq(t, "{tenant}.example.com", route.Info().Host)
+	assert.Eq(t, 1, route.Info().HandlerNum)
+
+	u := r.BuildURL("user", M{"{tenant}": "acme", "{id}": 5})
+	assert.Eq(t, "acme.example.com", u.Host)
+	assert.Eq(t, "/users/5", u.Path)
+
+	u = r.BuildRequestURL("user", NewBuildRequestURL().Host("x.test").Params(M{"{id}": 1}))
+	assert.Eq(t, "x.test", u.Host)
+
+	assert.Eq(t, "", r.BuildURL("wild").Host)
+}
@@ -0,0 +1,103 @@
+package core
+
+// With sets the metadata key of the route to value, for middlewares and
+// handlers to read with Context.RouteMeta: required scopes, rate limit
+// tiers, cache TTLs, docs.
+//
+//	r.GET("/reports", reports).With("scope", "reports:read").With("cacheTTL", time.Minute)
+//
+// Route metadata overrides the metadata of its groups, see
+// RouteGroup.With. It is stored in Opts.
+func (r *Route) With(key string, value any) *Route {
+	if r.group != nil && r.group.router.frozen.Load() {
+		panic("rux: cannot set route metadata after router is frozen")
+	}
+	if r.Opts == nil {
+		r.Opts = make(map[string]any, 1)
+	}
+	r.Opts[key] = value
+	for _, exp := range r.expansions {
+		exp.Opts = r.Opts
+	}
+	return r
+}
+
+// MetaValue returns the metadata value of key: set on the route, else on
+// its innermost group setting it.
+func (r *Route) MetaValue(key string) (any, bool) {
+	if v, ok := r.Opts[key]; ok {
+		return v, true
+	}
+	for g := r.group; g != nil; g = g.parent {
+		if v, ok := g.meta[key]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Meta returns the metadata of the route, inherited group metadata
+// included. The map is a copy.
+func (r *Route) Meta() map[string]any {
+	var groups []*RouteGroup
+	for g := r.group; g != nil; g = g.parent {
+		groups = append(groups, g)
+	}
+	meta := make(map[string]any, len(r.Opts))
+	for i := len(groups) - 1; i >= 0; i-- {
+		for k, v := range groups[i].meta {
+			meta[k] = v
+		}
+	}
+	for k, v := range r.Opts {
+		meta[k] = v
+	}
+	return meta
+}
+
+// With sets the metadata key to value for the routes of the group and its
+// nested groups, including routes added before the call. Routes and
+// nested groups override it.
+//
+//	admin := r.NewGroup("/admin").With("scope", "admin")
+func (g *RouteGroup) With(key string, value any) *RouteGroup {
+	r := g.router
+	r.lockUnfrozen("rux: cannot set group metadata after router is frozen")
+	if g.meta == nil {
+		g.meta = make(map[string]any, 1)
+	}
+	g.meta[key] = value
+	r.mu.Unlock()
+	return g
+}
+
+// RouteMeta returns the metadata value of key of the matched route, see
+// Route.With. It is nil, false without a matched route.
+func (c *Context) RouteMeta(key string) (any, bool) {
+	if c.matchedRoute == nil {
+		return nil, false
+	}
+	return c.matchedRoute.MetaValue(key)
+}
+
+// Meta returns the metadata value of key of the route matched by c,
+// converted to T. ok is false if the key is not set or not a T.
+//
+//	if scope, ok := rux.Meta[string](c, "scope"); ok && !hasScope(c, scope) {
+//		c.AbortWithStatus(403)
+//	}
+func Meta[T any](c *Context, key string) (v T, ok bool) {
+	if raw, found := c.RouteMeta(key); found {
+		v, ok = raw.(T)
+	}
+	return v, ok
+}
+
+// MetaOf returns the metadata value of key of route, converted to T. ok
+// is false if the key is not set or not a T.
+func MetaOf[T any](route *Route, key string) (v T, ok bool) {
+	if raw, found := route.MetaValue(key); found {
+		v, ok = raw.(T)
+	}
+	return v, ok
+}
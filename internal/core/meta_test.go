@@ -0,0 +1,63 @@
+package core
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestRoute_Meta(t *testing.T) {
+	r := New()
+	scope := func(c *Context) {
+		if s, ok := Meta[string](c, "scope"); ok && c.Query("scope") != s {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+	r.Use(scope)
+
+	admin := r.NewGroup("/admin").With("scope", "admin").With("tier", 1)
+	reports := admin.Group("/reports").With("tier", 2)
+	route := reports.GET("/{id}", func(c *Context) {
+		ttl, _ := Meta[time.Duration](c, "cacheTTL")
+		tier, _ := Meta[int](c, "tier")
+		c.Text(200, ttl.String()+" "+strconv.Itoa(tier))
+	}).With("cacheTTL", time.Minute)
+	r.GET("/posts[/{id}]", textHandler("posts")).With("scope", "posts")
+
+	assert.Eq(t, map[string]any{"scope": "admin", "tier": 2, "cacheTTL": time.Minute}, route.Meta())
+	v, ok := MetaOf[int](route, "tier")
+	assert.True(t, ok)
+	assert.Eq(t, 2, v)
+	_, ok = MetaOf[string](route, "tier")
+	assert.False(t, ok)
+	_, ok = route.MetaValue("missing")
+	assert.False(t, ok)
+
+	// Group metadata set after the route was added applies to it.
+	admin.With("scope", "root")
+
+	assert.Eq(t, 403, serve(r, GET, "/admin/reports/1?scope=admin").Code)
+	assert.Eq(t, "1m0s 2", serve(r, GET, "/admin/reports/1?scope=root").Body.String())
+	assert.Eq(t, 403, serve(r, GET, "/posts/1").Code)
+	assert.Eq(t, "posts", serve(r, GET, "/posts?scope=posts").Body.String())
+	assert.Eq(t, "posts", serve(r, GET, "/posts/1?scope=posts").Body.String())
+
+	assert.PanicsMsg(t, func() { route.With("x", 1) }, "rux: cannot set route metadata after router is frozen")
+	assert.PanicsMsg(t, func() { admin.With("x", 1) }, "rux: cannot set group metadata after router is frozen")
+}
+
+func TestContext_RouteMeta_NoRoute(t *testing.T) {
+	r := New()
+	var found bool
+	r.NotFound(func(c *Context) {
+		_, found = c.RouteMeta("scope")
+		c.Text(404, "none")
+	})
+	assert.Eq(t, "none", serve(r, GET, "/nope").Body.String())
+	assert.False(t, found)
+}
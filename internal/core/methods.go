@@ -1,5 +1,10 @@
 package core
 
+import (
+	"fmt"
+	"strings"
+)
+
 // HTTP method names. The root rux package re-exports these as constants
 // so external callers reference them as rux.GET, rux.POST, etc.
 const (
@@ -14,11 +19,16 @@ const (
 	TRACE   = "TRACE"
 )
 
-// methodCount is the total number of HTTP methods rux understands.
+// methodCount is the number of standard HTTP methods, indexes 0..8.
 const methodCount = 9
 
-// methodIndex maps an HTTP method string to a 0..8 array index.
-// Returns -1 for unknown methods.
+// maxMethods is the size of the per-method route tables: the standard
+// methods plus up to maxMethods-methodCount registered by
+// Router.RegisterMethod.
+const maxMethods = 32
+
+// methodIndex maps a standard HTTP method string to a 0..8 array index.
+// Returns -1 for other methods, see methodSet.index.
 func methodIndex(m string) int {
 	if len(m) == 0 {
 		return -1
@@ -61,3 +71,95 @@ func methodIndex(m string) int {
 	}
 	return -1
 }
+
+// methodSet is the immutable set of methods known to a Router.
+type methodSet struct {
+	// all is the standard methods in Any order, then registered ones.
+	all []string
+	// ext maps registered extension methods to their table index.
+	ext map[string]int
+}
+
+// stdMethods is the methodSet of routers without registered methods.
+var stdMethods = &methodSet{
+	all: []string{GET, POST, PUT, PATCH, DELETE, OPTIONS, HEAD, CONNECT, TRACE},
+}
+
+// index returns the table index of method m, or -1. Registered methods
+// are only looked up for non-standard methods.
+func (ms *methodSet) index(m string) int {
+	if idx := methodIndex(m); idx >= 0 {
+		return idx
+	}
+	if idx, ok := ms.ext[m]; ok {
+		return idx
+	}
+	return -1
+}
+
+// RegisterMethod adds extension HTTP methods to r, e.g. WebDAV's PROPFIND
+// or QUERY, with their own route tables. Names are uppercased and must be
+// HTTP tokens; known methods are ignored. Registered methods are part of
+// r.AnyMethods, the Allow headers of r and Any routes registered
+// afterwards. At most 23 methods can be registered per router.
+//
+// Register the methods before the routes using them: adding a route with
+// an unknown method panics, and so does RegisterMethod once r is frozen.
+// Route sets made by NewRouteSet start with the methods of r; mounted
+// routers register their own.
+//
+//	r.RegisterMethod("PROPFIND", "MKCOL", "LOCK")
+//	r.Add("/dav/{path:.*}", davHandler, "PROPFIND")
+func (r *Router) RegisterMethod(methods ...string) {
+	r.lockUnfrozen("rux: cannot register methods after router is frozen")
+	defer r.mu.Unlock()
+
+	cur := r.methods
+	next := &methodSet{
+		all: append([]string(nil), cur.all...),
+		ext: make(map[string]int, len(cur.ext)+len(methods)),
+	}
+	for m, idx := range cur.ext {
+		next.ext[m] = idx
+	}
+	for _, m := range methods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if !isToken(m) {
+			panic(fmt.Sprintf("rux: invalid HTTP method name %q", m))
+		}
+		if next.index(m) >= 0 {
+			continue
+		}
+		idx := methodCount + len(next.ext)
+		if idx >= maxMethods {
+			panic(fmt.Sprintf("rux: too many custom HTTP methods (limit %d)", maxMethods-methodCount))
+		}
+		next.ext[m] = idx
+		next.all = append(next.all, m)
+	}
+	r.methods = next
+}
+
+// AnyMethods returns the methods known to r: the standard ones, then
+// those added by RegisterMethod. The returned slice is shared — callers
+// must not mutate it.
+func (r *Router) AnyMethods() []string {
+	return r.methods.all
+}
+
+// isToken reports whether s is a non-empty HTTP token (RFC 9110).
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/gookit/goutil/x/assert"
@@ -28,3 +29,68 @@ func TestMethodIndex(t *testing.T) {
 		assert.Eq(t, c.want, methodIndex(c.method), "method=%s", c.method)
 	}
 }
+
+func TestRouter_RegisterMethod(t *testing.T) {
+	r := New()
+	r.RegisterMethod("propfind", "MKCOL", "QUERY", "GET", "MKCOL")
+
+	assert.Eq(t, methodCount, r.methods.index("PROPFIND"))
+	assert.Eq(t, methodCount+1, r.methods.index("MKCOL"))
+	assert.Eq(t, methodCount+2, r.methods.index("QUERY"))
+	assert.Eq(t, -1, r.methods.index("propfind"))
+	assert.Len(t, r.AnyMethods(), methodCount+3)
+	assert.Eq(t, "QUERY", r.AnyMethods()[len(r.AnyMethods())-1])
+
+	// Other routers and the package-level list are not affected.
+	assert.Len(t, AnyMethods(), methodCount)
+	assert.Eq(t, -1, New().methods.index("QUERY"))
+	assert.Panics(t, func() { New().Add("/q", textHandler("q"), "QUERY") })
+
+	assert.PanicsMsg(t, func() { r.RegisterMethod("BAD METHOD") }, `rux: invalid HTTP method name "BAD METHOD"`)
+	assert.Panics(t, func() { r.RegisterMethod("") })
+
+	r.Freeze()
+	assert.PanicsMsg(t, func() { r.RegisterMethod("LOCK") }, "rux: cannot register methods after router is frozen")
+}
+
+func TestRouter_RegisterMethod_Limit(t *testing.T) {
+	r := New()
+	assert.PanicsMsg(t, func() {
+		for i := 0; i <= maxMethods-methodCount; i++ {
+			r.RegisterMethod(fmt.Sprintf("X-%d", i))
+		}
+	}, fmt.Sprintf("rux: too many custom HTTP methods (limit %d)", maxMethods-methodCount))
+	// The limit is per router.
+	New().RegisterMethod("X-0")
+}
+
+func TestRouter_CustomMethods(t *testing.T) {
+	r := New(HandleMethodNotAllowed)
+	r.RegisterMethod("PROPFIND", "QUERY")
+	r.Add("/dav/{path:.*}", textHandler("propfind"), "propfind")
+	r.Add("/search", textHandler("query"), "QUERY")
+	r.GET("/search", textHandler("get"))
+	r.Any("/any", textHandler("any"))
+	// Unregistered methods are still rejected.
+	assert.PanicsMsg(t, func() {
+		r.Add("/x", textHandler("x"), "LOCK")
+	}, `rux: invalid HTTP method "LOCK"`)
+
+	assert.Eq(t, "propfind", serve(r, "PROPFIND", "/dav/a/b").Body.String())
+	assert.Eq(t, "query", serve(r, "QUERY", "/search").Body.String())
+	assert.Eq(t, "any", serve(r, "QUERY", "/any").Body.String())
+
+	w := serve(r, POST, "/search")
+	assert.Eq(t, 405, w.Code)
+	assert.Eq(t, "GET, HEAD, QUERY", w.Header().Get("Allow"))
+
+	assert.Eq(t, 405, serve(r, "LOCK", "/search").Code)
+
+	assert.StrContains(t, r.String(), "QUERY")
+
+	// Route sets start with the methods of r.
+	rs := r.NewRouteSet()
+	rs.Add("/search", textHandler("query v2"), "QUERY")
+	r.Swap(rs)
+	assert.Eq(t, "query v2", serve(r, "QUERY", "/search").Body.String())
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// mountParam is the wildcard param that captures the path below a mount
+// prefix. mountSuffix is appended to the prefix on registration so a
+// single route matches both the prefix itself and everything below it.
+const (
+	mountParam  = "mountpath"
+	mountSuffix = "[/{" + mountParam + ":.*}]"
+)
+
+// Mount routes every request at or below prefix to sub, an independently
+// built Router with its own global middleware, NotFound, OnError and
+// options. The sub-router sees the request path with prefix stripped.
+// Errors and panics sub has no handler for use those of the mount route.
+//
+// The parent's global and group middlewares run before the sub-router.
+// Routes registered on the parent itself under prefix take precedence
+// over the mount. sub is frozen together with the parent.
+//
+//	users := rux.New()
+//	users.GET("/{id}", showUser)
+//	r.Mount("/users", users) // GET /users/42 -> users: GET /42
+func (r *Router) Mount(prefix string, sub *Router, middles ...HandlerFunc) *Route {
+	if sub == nil {
+		panic("rux: Mount sub-router cannot be nil")
+	}
+	if sub == r {
+		panic("rux: cannot Mount a router on itself")
+	}
+	route := r.mountRoute(prefix, func(c *Context) {
+		sub.serveMounted(c, stripPrefixRequest(c, mountParam))
+	}, middles)
+	route.mounted = sub
+	// The matched routes are the copies of the optional mount path.
+	for _, exp := range route.expansions {
+		exp.mounted = sub
+	}
+	r.mounts = append(r.mounts, sub)
+	return route
+}
+
+// MountHandler routes every request at or below prefix to h, with prefix
+// stripped from the request path (like http.StripPrefix).
+//
+//	r.MountHandler("/debug/vars", expvar.Handler())
+func (r *Router) MountHandler(prefix string, h http.Handler, middles ...HandlerFunc) *Route {
+	if h == nil {
+		panic("rux: MountHandler handler cannot be nil")
+	}
+	return r.mountRoute(prefix, func(c *Context) {
+		h.ServeHTTP(c.Resp, stripPrefixRequest(c, mountParam))
+	}, middles)
+}
+
+// mountRoute registers the any-method catch-all route for a mount.
+func (r *Router) mountRoute(prefix string, h HandlerFunc, middles []HandlerFunc) *Route {
+	prefix = strings.TrimRight(strings.TrimSpace(prefix), "/")
+	if strings.ContainsAny(prefix, "*[") {
+		panic("rux: mount prefix cannot contain wildcard or optional segments: " + prefix)
+	}
+
+	route := newRoute(prefix+mountSuffix, h, r.AnyMethods())
+	route.Use(middles...)
+	return r.AddRoute(route)
+}
+
+// serveMounted serves req on r for the mount route matched by parent.
+// Errors and panics r has no handler for fall back to the handlers of
+// the mount route, its groups and the parent router.
+func (r *Router) serveMounted(parent *Context, req *http.Request) {
+	if !r.frozen.Load() {
+		r.Freeze()
+	}
+	ctx := r.ctxPool.Get().(*Context)
+	ctx.Init(parent.Resp, req)
+	ctx.parent = parent
+	r.handle(ctx)
+	ctx.parent = nil
+	r.ctxPool.Put(ctx)
+}
+
+// Mounted returns the sub-router of a route registered by Router.Mount,
+// or nil for any other route.
+func (r *Route) Mounted() *Router { return r.mounted }
+
+// MountPrefix returns the group-prefixed path a mount route serves, or ""
+// for routes not registered by Mount / MountHandler.
+func (r *Route) MountPrefix() string {
+	if prefix, ok := strings.CutSuffix(r.originalPath, mountSuffix); ok {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+	return ""
+}
+
+// stripPrefixRequest returns a shallow copy of c.Req with the matched
+// route prefix stripped from the path, like http.StripPrefix. The new path
+// is the value of the wildcard param restParam, so group prefixes, prefix
+// params and the slashes cleaned by path normalization are stripped too;
+// a trailing slash is kept. Used by mounts and the static directory
+// helpers.
+func stripPrefixRequest(c *Context, restParam string) *http.Request {
+	p := "/" + c.Param(restParam)
+	if strings.HasSuffix(c.Req.URL.Path, "/") && !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	rp := ""
+	if c.router.useEncodedPath {
+		// The param is a segment of the escaped path.
+		if up, err := url.PathUnescape(p); err == nil {
+			p, rp = up, p
+		}
+	}
+
+	req := new(http.Request)
+	*req = *c.Req
+	req.URL = new(url.URL)
+	*req.URL = *c.Req.URL
+	req.URL.Path = p
+	req.URL.RawPath = rp
+	return req
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestRouter_Mount_DispatchesToSubRouter(t *testing.T) {
+	var order []string
+	sub := New()
+	sub.Use(func(c *Context) { order = append(order, "sub-global"); c.Next() })
+	sub.GET("/", textHandler("sub-root"))
+	sub.GET("/{id}", func(c *Context) {
+		assert.Same(t, sub, c.Router())
+		c.Text(200, "user "+c.Param("id")+" at "+c.URL().Path)
+	})
+	sub.NotFound(func(c *Context) { c.Text(404, "sub 404") })
+
+	r := New()
+	r.Use(func(c *Context) { order = append(order, "parent-global"); c.Next() })
+	r.GET("/users/me", textHandler("parent me"))
+	r.Mount("/users", sub)
+
+	w := serve(r, "GET", "/users/42")
+	assert.Eq(t, "user 42 at /42", w.Body.String())
+	assert.Eq(t, []string{"parent-global", "sub-global"}, order)
+
+	assert.Eq(t, "sub-root", serve(r, "GET", "/users").Body.String())
+	assert.Eq(t, "sub-root", serve(r, "GET", "/users/").Body.String())
+	// Parent routes under the prefix take precedence.
+	assert.Eq(t, "parent me", serve(r, "GET", "/users/me").Body.String())
+
+	w = serve(r, "GET", "/users/1/2")
+	assert.Eq(t, 404, w.Code)
+	assert.Eq(t, "sub 404", w.Body.String())
+	assert.Eq(t, 404, serve(r, "GET", "/other").Code)
+
+	assert.True(t, sub.Frozen())
+}
+
+func TestRouter_Mount_OwnOnError(t *testing.T) {
+	sub := New()
+	sub.GET("/fail", func(c *Context) { c.AddError(errors.New("boom")) })
+	sub.OnError = func(c *Context) { c.Text(500, "sub error: "+c.FirstError().Error()) }
+
+	r := New()
+	r.Group("/v1", func() {
+		r.Mount("/mod", sub)
+	})
+
+	w := serve(r, "GET", "/v1/mod/fail")
+	assert.Eq(t, 500, w.Code)
+	assert.Eq(t, "sub error: boom", w.Body.String())
+}
+
+func TestRouter_Mount_Panics(t *testing.T) {
+	r := New()
+	assert.Panics(t, func() { r.Mount("/x", nil) })
+	assert.Panics(t, func() { r.Mount("/x", r) })
+	assert.Panics(t, func() { r.MountHandler("/x", nil) })
+	assert.Panics(t, func() { r.MountHandler("/x/*all", http.NotFoundHandler()) })
+}
+
+func TestRouter_MountHandler_StripsPrefix(t *testing.T) {
+	r := New()
+	r.Group("/api", func() {
+		r.MountHandler("/legacy/", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte(req.Method + " " + req.URL.Path))
+		}))
+	})
+
+	assert.Eq(t, "POST /a/b", serve(r, "POST", "/api/legacy/a/b").Body.String())
+	assert.Eq(t, "GET /", serve(r, "GET", "/api/legacy").Body.String())
+	// Leading slashes are collapsed when matching.
+	assert.Eq(t, "GET /a/b", serve(r, "GET", "http://example.com//api/legacy/a/b").Body.String())
+	assert.Eq(t, "GET /dir/", serve(r, "GET", "/api/legacy/dir/").Body.String())
+
+	r = New(UseEncodedPath)
+	r.MountHandler("/files", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(req.URL.Path + " " + req.URL.EscapedPath()))
+	}))
+	assert.Eq(t, "/a/b%c /a%2Fb%25c", serve(r, "GET", "/files/a%2Fb%25c").Body.String())
+}
+
+func TestRouter_Mount_RoutesAndString(t *testing.T) {
+	sub := New()
+	sub.GET("/list", textHandler("l"))
+	sub.POST("/{id}", textHandler("p"))
+
+	r := New()
+	r.GET("/home", textHandler("h"))
+	route := r.Mount("/admin", sub)
+	r.MountHandler("/raw", http.NotFoundHandler())
+
+	assert.Same(t, sub, route.Mounted())
+	assert.Eq(t, "/admin", route.MountPrefix())
+	assert.Eq(t, "", r.routeList[0].MountPrefix())
+	// Requests match the copies of the optional mount path, which report
+	// the sub-router too.
+	for _, path := range []string{"/admin", "/admin/list"} {
+		matched, _, ok := r.Match(GET, path)
+		assert.True(t, ok, path)
+		assert.NotSame(t, route, matched, path)
+		assert.Same(t, sub, matched.Mounted(), path)
+	}
+
+	var paths []string
+	for _, info := range r.Routes() {
+		paths = append(paths, info.Path)
+	}
+	assert.Eq(t, []string{"/home", "/admin[/{mountpath:.*}]", "/admin/list", "/admin/:id", "/raw[/{mountpath:.*}]"}, paths)
+
+	s := r.String()
+	assert.True(t, strings.Contains(s, "    GET"))
+	assert.True(t, strings.Contains(s, "/admin/list"))
+}
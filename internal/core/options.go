@@ -0,0 +1,111 @@
+package core
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// HandleOptions answers OPTIONS requests for every registered path that
+// has no explicit OPTIONS route: the Allow header lists the methods
+// registered for the path, then the GlobalOptions chain runs (204 No
+// Content by default). The Allow headers of static paths are computed on
+// Freeze.
+//
+// Allow lists every method with a route matching the path, as the Allow
+// header of 405 responses does, which then also lists OPTIONS.
+func HandleOptions(r *Router) { r.handleOptions = true }
+
+// GlobalOptions sets handlers for the automatic OPTIONS responses of
+// HandleOptions, e.g. a CORS middleware answering preflight requests.
+// The Allow header is already set and the methods are available as
+// CTXAllowedMethods. Unless the chain aborts or sets a status, the
+// default 204 No Content response is written after it.
+//
+//	r := rux.New(rux.HandleOptions)
+//	r.GlobalOptions(corsMiddleware)
+func (r *Router) GlobalOptions(handlers ...HandlerFunc) {
+	r.lockUnfrozen("rux: cannot set GlobalOptions after router is frozen")
+	defer r.mu.Unlock()
+	chain := make(HandlersChain, 0, len(handlers)+1)
+	chain = append(chain, handlers...)
+	r.optionsChain = append(chain, internalOptionsHandler)
+}
+
+var internalOptionsHandler HandlerFunc = func(c *Context) {
+	if c.writer.Status() == 0 && !c.writer.Written() {
+		c.Resp.WriteHeader(http.StatusNoContent)
+	}
+}
+
+var internalOptionsChain = HandlersChain{internalOptionsHandler}
+
+// allowEntry is the sorted list of allowed methods and its header value.
+type allowEntry struct {
+	methods []string
+	header  string
+}
+
+func newAllowEntry(methods []string) allowEntry {
+	if !containsString(methods, OPTIONS) {
+		methods = append(methods, OPTIONS)
+	}
+	sort.Strings(methods)
+	return allowEntry{methods: methods, header: strings.Join(methods, ", ")}
+}
+
+// buildAllow computes the Allow entry of every static path of the
+// host-less routes.
+func (t *routeTable) buildAllow(ms *methodSet) {
+	static := make(map[string][]string)
+	for _, m := range ms.all {
+		for p := range t.staticRoutes[ms.index(m)] {
+			static[p] = nil
+		}
+	}
+	t.allow = make(map[string]allowEntry, len(static))
+	for p := range static {
+		// A static path can also match dynamic routes of other methods.
+		t.allow[p] = newAllowEntry(t.allowedMethods(ms, nil, "", p))
+	}
+}
+
+// serveOptions answers an OPTIONS request for path by HandleOptions. It
+// reports false if no route matches path.
+func (r *Router) serveOptions(ctx *Context, host *hostRoutes, path string) bool {
+	e, ok := r.allowedFor(host, path)
+	if !ok {
+		return false
+	}
+
+	ctx.SetHeader("Allow", e.header)
+	ctx.Set(CTXAllowedMethods, e.methods)
+	ctx.SetHandlers(r.optionsHandlers())
+	ctx.Next()
+	return true
+}
+
+// allowedFor returns the Allow entry of path. It lists the methods the
+// 405 response of HandleMethodNotAllowed would: the Allow entries of
+// static host-less paths are computed on Freeze, the others by looking up
+// path in the route table of every method.
+func (r *Router) allowedFor(host *hostRoutes, path string) (allowEntry, bool) {
+	if host == nil {
+		if e, ok := r.routeTable.allow[path]; ok {
+			return e, true
+		}
+	}
+	methods := r.findAllowedMethods(host, "", path)
+	if len(methods) == 0 {
+		return allowEntry{}, false
+	}
+	return newAllowEntry(methods), true
+}
+
+// optionsHandlers returns the chain answering automatic OPTIONS requests.
+func (r *Router) optionsHandlers() HandlersChain {
+	if len(r.optionsChain) == 0 {
+		return internalOptionsChain
+	}
+	return r.optionsChain
+}
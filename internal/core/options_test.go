@@ -0,0 +1,100 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestHandleOptions(t *testing.T) {
+	r := New(HandleOptions)
+	r.GET("/users", textHandler("list"))
+	r.POST("/users", textHandler("create"))
+	r.GET("/users/{id}", textHandler("show"))
+	r.PUT("/users/{uid}", textHandler("update"))
+	r.DELETE("/users/new", textHandler("drop"))
+	r.GET("/files/{id:\\d+}", textHandler("file"))
+	r.OPTIONS("/custom", textHandler("own options"))
+	r.GET("/custom", textHandler("custom"))
+
+	w := serve(r, OPTIONS, "/users")
+	assert.Eq(t, 204, w.Code)
+	assert.Eq(t, "GET, HEAD, OPTIONS, POST", w.Header().Get("Allow"))
+
+	// Param names do not matter.
+	w = serve(r, OPTIONS, "/users/7")
+	assert.Eq(t, "GET, HEAD, OPTIONS, PUT", w.Header().Get("Allow"))
+
+	// A static path also lists dynamic routes of other methods.
+	w = serve(r, OPTIONS, "/users/new")
+	assert.Eq(t, "DELETE, GET, HEAD, OPTIONS, PUT", w.Header().Get("Allow"))
+
+	assert.Eq(t, "GET, HEAD, OPTIONS", serve(r, OPTIONS, "/files/1").Header().Get("Allow"))
+	assert.Eq(t, 404, serve(r, OPTIONS, "/files/abc").Code)
+	assert.Eq(t, 404, serve(r, OPTIONS, "/nope").Code)
+
+	// Explicit OPTIONS routes win.
+	assert.Eq(t, "own options", serve(r, OPTIONS, "/custom").Body.String())
+}
+
+func TestGlobalOptions(t *testing.T) {
+	cors := func(c *Context) {
+		c.SetHeader("Access-Control-Allow-Origin", "*")
+		methods := c.SafeGet(CTXAllowedMethods).([]string)
+		c.SetHeader("Access-Control-Allow-Methods", methods[0])
+		c.Next()
+	}
+
+	r := New(HandleOptions)
+	r.GlobalOptions(cors)
+	r.PATCH("/items/{id}", textHandler("patch"))
+
+	w := serve(r, OPTIONS, "/items/1")
+	assert.Eq(t, 204, w.Code)
+	assert.Eq(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Eq(t, "OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Eq(t, "OPTIONS, PATCH", w.Header().Get("Allow"))
+
+	r2 := New(HandleOptions)
+	r2.GlobalOptions(func(c *Context) { c.Text(200, "preflight") })
+	r2.GET("/x", textHandler("x"))
+	w = serve(r2, OPTIONS, "/x")
+	assert.Eq(t, 200, w.Code)
+	assert.Eq(t, "preflight", w.Body.String())
+}
+
+func TestHandleOptions_HostsAndSwap(t *testing.T) {
+	r := New(HandleOptions)
+	r.Host("api.example.com", func() {
+		r.DELETE("/things/{id}", textHandler("del"))
+	})
+	r.GET("/things/{id}", textHandler("get"))
+
+	w := serve(r, OPTIONS, "/things/1", "Host", "api.example.com")
+	assert.Eq(t, "DELETE, GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+	w = serve(r, OPTIONS, "/things/1", "Host", "other.com")
+	assert.Eq(t, "GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+
+	rs := r.NewRouteSet()
+	rs.POST("/jobs", textHandler("job"))
+	r.Swap(rs)
+	assert.Eq(t, "OPTIONS, POST", serve(r, OPTIONS, "/jobs").Header().Get("Allow"))
+	assert.Eq(t, 404, serve(r, OPTIONS, "/things/1").Code)
+}
+
+// OPTIONS and 405 responses list the same methods, also for routes of
+// different patterns matching a path.
+func TestHandleOptions_AgreesWithMethodNotAllowed(t *testing.T) {
+	r := New(HandleOptions, HandleMethodNotAllowed)
+	r.GET("/users/{id}", textHandler("show"))
+	r.DELETE("/users/*rest", textHandler("drop"))
+
+	assert.Eq(t, "DELETE, GET, HEAD, OPTIONS", serve(r, OPTIONS, "/users/7").Header().Get("Allow"))
+	w := serve(r, POST, "/users/7")
+	assert.Eq(t, 405, w.Code)
+	assert.Eq(t, "DELETE, GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+	assert.Eq(t, "DELETE, OPTIONS", serve(r, OPTIONS, "/users/7/posts").Header().Get("Allow"))
+
+	assert.PanicsMsg(t, func() { r.GlobalOptions(textHandler("late")) },
+		"rux: cannot set GlobalOptions after router is frozen")
+}
@@ -1,26 +1,50 @@
 package core
 
-import "strconv"
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
 
-// MaxParams is the maximum number of path parameters per route.
-// Inlined storage in Context avoids any heap allocation for params.
-// Registering a route that exceeds this limit panics.
+// MaxParams is the default maximum number of path parameters per route,
+// host params included. Registering a route that exceeds the limit
+// panics; ParamsLimit changes it per router.
 const MaxParams = 16
 
+// maxParamsLimit bounds ParamsLimit: the param count is a uint8.
+const maxParamsLimit = 255
+
+// ParamsLimit sets the maximum number of path params per route (host
+// params included), MaxParams by default. n must be in 1..255.
+func ParamsLimit(n int) func(*Router) {
+	if n < 1 || n > maxParamsLimit {
+		panic("rux: ParamsLimit must be in 1.." + strconv.Itoa(maxParamsLimit))
+	}
+	return func(r *Router) { r.paramsLimit = n }
+}
+
 // Param is a single path parameter.
 type Param struct {
 	Key   string
 	Value string
 }
 
-// Params is a fixed-capacity inline parameter container.
-// It lives directly inside Context, providing zero-allocation parameter
-// passing when the Context itself is reused via sync.Pool.
+// Params is the path parameter container of a Context.
+//
+// Its storage is allocated once per pooled Context, sized on Freeze to the
+// largest param count of the router's routes, so parameter passing does
+// not allocate. A zero Params is ready to use and grows on demand.
 type Params struct {
-	data [MaxParams]Param
+	data []Param
 	n    uint8
 }
 
+// newParams returns Params with room for n params.
+func newParams(n int) Params {
+	return Params{data: make([]Param, n)}
+}
+
 // Len returns the number of parameters.
 func (p *Params) Len() int { return int(p.n) }
 
@@ -45,6 +69,7 @@ func (p *Params) Has(name string) bool {
 }
 
 // Int parses the named parameter as int, returning 0 on miss or parse error.
+// Use Int64 to tell those apart.
 func (p *Params) Int(name string) int {
 	if v := p.Get(name); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
@@ -54,27 +79,152 @@ func (p *Params) Int(name string) int {
 	return 0
 }
 
+// ErrParamMissing is wrapped by the ParamError of a missing path param.
+var ErrParamMissing = errors.New("missing")
+
+// ParamError is returned by the typed Params accessors for a missing or
+// malformed path param. It maps to 400 Bad Request.
+type ParamError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+// Error implements error.
+func (e *ParamError) Error() string {
+	if errors.Is(e.Err, ErrParamMissing) {
+		return "path param " + strconv.Quote(e.Name) + " is missing"
+	}
+	return "path param " + strconv.Quote(e.Name) + ": invalid value " + strconv.Quote(e.Value) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the parse error.
+func (e *ParamError) Unwrap() error { return e.Err }
+
+// StatusCode implements StatusCoder.
+func (e *ParamError) StatusCode() int { return 400 }
+
+// lookup returns the value of the named param, or a ParamError if missing.
+func (p *Params) lookup(name string) (string, error) {
+	for i := uint8(0); i < p.n; i++ {
+		if p.data[i].Key == name {
+			return p.data[i].Value, nil
+		}
+	}
+	return "", &ParamError{Name: name, Err: ErrParamMissing}
+}
+
+// ParseParam parses the named param of p with parse. A missing param or a
+// parse error is returned as a *ParamError.
+//
+//	id, err := rux.ParseParam(c.Params(), "id", uuid.Parse)
+func ParseParam[T any](p *Params, name string, parse func(string) (T, error)) (T, error) {
+	v, err := p.lookup(name)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	out, err := parse(v)
+	if err != nil {
+		return out, &ParamError{Name: name, Value: v, Err: err}
+	}
+	return out, nil
+}
+
+// Int64 parses the named param as a base 10 int64.
+func (p *Params) Int64(name string) (int64, error) {
+	return ParseParam(p, name, func(s string) (int64, error) {
+		return strconv.ParseInt(s, 10, 64)
+	})
+}
+
+// Uint parses the named param as a base 10 uint64.
+func (p *Params) Uint(name string) (uint64, error) {
+	return ParseParam(p, name, func(s string) (uint64, error) {
+		return strconv.ParseUint(s, 10, 64)
+	})
+}
+
+// Bool parses the named param with strconv.ParseBool.
+func (p *Params) Bool(name string) (bool, error) {
+	return ParseParam(p, name, strconv.ParseBool)
+}
+
+// Float parses the named param as a float64.
+func (p *Params) Float(name string) (float64, error) {
+	return ParseParam(p, name, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+}
+
+// Time parses the named param with layout, time.RFC3339 if empty.
+func (p *Params) Time(name, layout string) (time.Time, error) {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return ParseParam(p, name, func(s string) (time.Time, error) {
+		return time.Parse(layout, s)
+	})
+}
+
+// UUID validates the named param as a hyphenated UUID and returns it in
+// lower case.
+func (p *Params) UUID(name string) (string, error) {
+	return ParseParam(p, name, parseUUID)
+}
+
+var errInvalidUUID = errors.New("not a UUID")
+
+// parseUUID checks the 8-4-4-4-12 hex form of s.
+func parseUUID(s string) (string, error) {
+	if len(s) != 36 {
+		return "", errInvalidUUID
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return "", errInvalidUUID
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return "", errInvalidUUID
+			}
+		}
+	}
+	return strings.ToLower(s), nil
+}
+
 // Snapshot returns a heap-allocated copy of the params slice.
 // Use this when you need to retain params beyond the handler scope
 // (e.g., goroutines, async logging).
 func (p *Params) Snapshot() []Param {
 	out := make([]Param, p.n)
-	for i := uint8(0); i < p.n; i++ {
-		out[i] = p.data[i]
-	}
+	copy(out, p.data[:p.n])
 	return out
 }
 
 // Reset clears the params (called by Context.Reset on pool return).
 func (p *Params) Reset() { p.n = 0 }
 
-// append adds a parameter. Panics if MaxParams is exceeded.
-// Callers in tree.lookup should ensure tree.maxParams <= MaxParams at registration.
+// append adds a parameter. The storage grows if needed, which only
+// happens for Params not sized by a router (or after Swap installed routes
+// with more params); the grown storage is kept.
 func (p *Params) append(key, value string) {
-	if p.n >= MaxParams {
-		panic("rux: params overflow (MaxParams=" + strconv.Itoa(MaxParams) + ")")
+	if int(p.n) == len(p.data) {
+		p.grow()
 	}
 	p.data[p.n].Key = key
 	p.data[p.n].Value = value
 	p.n++
 }
+
+func (p *Params) grow() {
+	if len(p.data) >= maxParamsLimit {
+		panic("rux: params overflow (limit " + strconv.Itoa(maxParamsLimit) + ")")
+	}
+	data := make([]Param, min(max(2*len(p.data), 4), maxParamsLimit))
+	copy(data, p.data)
+	p.data = data
+}
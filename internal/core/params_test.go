@@ -1,7 +1,11 @@
 package core
 
 import (
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gookit/goutil/x/assert"
 )
@@ -50,10 +54,103 @@ func TestParams_Snapshot(t *testing.T) {
 	assert.Eq(t, "v", p.Get("k"))
 }
 
-func TestParams_OverflowPanics(t *testing.T) {
+func TestParams_GrowsPastSizedStorage(t *testing.T) {
+	p := newParams(2)
+	for i := 0; i < MaxParams+1; i++ {
+		p.append("k"+strconv.Itoa(i), strconv.Itoa(i))
+	}
+	assert.Eq(t, MaxParams+1, p.Len())
+	assert.Eq(t, "16", p.Get("k16"))
+	assert.Eq(t, "0", p.Get("k0"))
+}
+
+func TestParams_TypedAccessors(t *testing.T) {
 	var p Params
-	for i := 0; i < MaxParams; i++ {
-		p.append("k", "v")
+	p.append("id", "-42")
+	p.append("n", "7")
+	p.append("ok", "true")
+	p.append("f", "1.5")
+	p.append("at", "2026-01-02T03:04:05Z")
+	p.append("day", "2026-01-02")
+	p.append("uid", "0F8FAD5B-D9CB-469F-A165-70867728950E")
+	p.append("bad", "x1")
+
+	i, err := p.Int64("id")
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(-42), i)
+	u, err := p.Uint("n")
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(7), u)
+	b, err := p.Bool("ok")
+	assert.NoErr(t, err)
+	assert.True(t, b)
+	f, err := p.Float("f")
+	assert.NoErr(t, err)
+	assert.Eq(t, 1.5, f)
+	at, err := p.Time("at", "")
+	assert.NoErr(t, err)
+	assert.Eq(t, 2026, at.Year())
+	day, err := p.Time("day", time.DateOnly)
+	assert.NoErr(t, err)
+	assert.Eq(t, time.January, day.Month())
+	uid, err := p.UUID("uid")
+	assert.NoErr(t, err)
+	assert.Eq(t, "0f8fad5b-d9cb-469f-a165-70867728950e", uid)
+
+	_, err = p.Int64("bad")
+	var pe *ParamError
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, "x1", pe.Value)
+	assert.Eq(t, 400, pe.StatusCode())
+	assert.StrContains(t, err.Error(), `path param "bad": invalid value "x1"`)
+
+	_, err = p.Uint("id")
+	assert.Err(t, err)
+	_, err = p.UUID("n")
+	assert.Err(t, err)
+
+	_, err = p.Bool("missing")
+	assert.True(t, errors.Is(err, ErrParamMissing))
+	assert.Eq(t, `path param "missing" is missing`, err.Error())
+
+	upper, err := ParseParam(&p, "bad", func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, "X1", upper)
+}
+
+func TestRouter_ParamsLimit(t *testing.T) {
+	path := "/a/{p1}/{p2}/{p3}"
+	assert.PanicsMsg(t, func() {
+		New(ParamsLimit(2)).GET(path, textHandler("x"))
+	}, "rux: route GET /a/:p1/:p2/:p3 has 3 path params, more than the limit of 2 (see ParamsLimit)")
+
+	r := New(ParamsLimit(2))
+	assert.Panics(t, func() {
+		r.Host("{tenant}.example.com", func() {
+			r.GET("/a/{p1}/{p2}", textHandler("x"))
+		})
+	})
+	assert.Panics(t, func() { ParamsLimit(0) })
+
+	// Beyond the default limit, still allocation-free per request.
+	var b strings.Builder
+	var req strings.Builder
+	for i := 0; i < 20; i++ {
+		b.WriteString("/{p" + strconv.Itoa(i) + "}")
+		req.WriteString("/" + strconv.Itoa(i))
 	}
-	assert.Panics(t, func() { p.append("overflow", "x") })
+	r = New(ParamsLimit(20))
+	r.GET(b.String(), func(c *Context) { c.Text(200, c.Param("p19")) })
+	assert.Eq(t, "19", serve(r, GET, req.String()).Body.String())
+	assert.Eq(t, 20, r.maxParams)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		ctx := r.ctxPool.Get().(*Context)
+		ctx.params.Reset()
+		r.routeTable.match(methodIndex(GET), req.String(), &ctx.params)
+		r.ctxPool.Put(ctx)
+	})
+	assert.Eq(t, float64(0), allocs)
 }
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// RedirectTrailingSlash redirects a request that has no route, but
+// would match with a trailing slash added or removed, to that registered
+// variant. Without StrictLastSlash routes have no trailing slash, so
+// /users/ redirects to /users.
+//
+// GET requests get 301 Moved Permanently, others 308 Permanent Redirect,
+// which keeps the method and body.
+func RedirectTrailingSlash(r *Router) { r.redirectTrailingSlash = true }
+
+// RedirectFixedPath redirects a request that has no route to its cleaned
+// path (no "..", "." or "//" segments) if that matches, else to the
+// registered spelling of a case-insensitive match, e.g. /USERS/../Items
+// to /items. With RedirectTrailingSlash a trailing slash is fixed too.
+//
+// Status codes are as for RedirectTrailingSlash.
+func RedirectFixedPath(r *Router) { r.redirectFixedPath = true }
+
+// redirectPath returns the path a request for path (already formatted)
+// should be redirected to, or "" if there is none. It only looks at
+// routes of the method at idx in the route set rt: in host and the
+// host-less table.
+func (r *Router) redirectPath(rt *Router, host *hostRoutes, idx int, path string, ps *Params) string {
+	snap := ps.n
+	defer func() { ps.n = snap }()
+
+	if r.redirectTrailingSlash && path != "/" {
+		if alt := toggleTrailingSlash(path); rt.matchIn(host, idx, alt, ps) != nil {
+			return alt
+		}
+		ps.n = snap
+	}
+	if !r.redirectFixedPath {
+		return ""
+	}
+
+	cleaned := cleanPath(path)
+	if cleaned != path && rt.matchIn(host, idx, cleaned, ps) != nil {
+		return cleaned
+	}
+	if fixed, ok := rt.findFold(host, idx, cleaned); ok {
+		return fixed
+	}
+	if r.redirectTrailingSlash && cleaned != "/" {
+		if fixed, ok := rt.findFold(host, idx, toggleTrailingSlash(cleaned)); ok {
+			return fixed
+		}
+	}
+	return ""
+}
+
+// matchIn looks up path in the table of host, then the host-less table.
+func (r *Router) matchIn(host *hostRoutes, idx int, path string, ps *Params) *Route {
+	if host != nil {
+		if route := host.match(idx, path, ps); route != nil {
+			return route
+		}
+	}
+	return r.routeTable.match(idx, path, ps)
+}
+
+// findFold is the case-insensitive matchIn. It returns the registered
+// spelling of path.
+func (r *Router) findFold(host *hostRoutes, idx int, path string) (string, bool) {
+	if host != nil {
+		if fixed, ok := host.findFold(idx, path); ok {
+			return fixed, true
+		}
+	}
+	return r.routeTable.findFold(idx, path)
+}
+
+// findFold returns the registered spelling of path for the method at idx,
+// matched ASCII case-insensitively. Param values keep the request case.
+func (t *routeTable) findFold(idx int, path string) (string, bool) {
+	if idx < 0 {
+		return "", false
+	}
+	if m := t.staticRoutes[idx]; m != nil {
+		// Off the hot path: a linear scan, smallest spelling first so the
+		// result is deterministic.
+		var found []string
+		for p := range m {
+			if strings.EqualFold(p, path) {
+				found = append(found, p)
+			}
+		}
+		if len(found) > 0 {
+			sort.Strings(found)
+			return found[0], true
+		}
+	}
+	if tree := t.dynamicTrees[idx]; tree != nil {
+		return tree.findFold(path)
+	}
+	return "", false
+}
+
+// redirectTo redirects the request to path, keeping the query string.
+// GET requests get 301, others 308.
+func redirectTo(c *Context, path string) {
+	code := http.StatusMovedPermanently
+	if c.Req.Method != http.MethodGet {
+		code = http.StatusPermanentRedirect
+	}
+	loc := c.mountPrefix() + path
+	if q := c.Req.URL.RawQuery; q != "" {
+		loc += "?" + q
+	}
+	http.Redirect(c.Resp, c.Req, loc, code)
+}
+
+// mountPrefix returns the path prefix stripped by the mounts the request
+// passed through, "" for a request not served by a mounted router.
+func (c *Context) mountPrefix() string {
+	if c.parent == nil {
+		return ""
+	}
+	outer := c.parent.Req.URL.Path
+	prefix := strings.TrimSuffix(outer, c.Req.URL.Path)
+	if len(prefix) == len(outer) {
+		// The mount prefix itself was requested: the sub path is "/".
+		prefix = strings.TrimRight(outer, "/")
+	}
+	return c.parent.mountPrefix() + prefix
+}
+
+// toggleTrailingSlash adds a trailing slash to p, or removes it.
+func toggleTrailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return p[:len(p)-1]
+	}
+	return p + "/"
+}
+
+// cleanPath is path.Clean that keeps a trailing slash.
+func cleanPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestRedirectTrailingSlash(t *testing.T) {
+	r := New(RedirectTrailingSlash)
+	r.GET("/users", textHandler("users"))
+	r.POST("/users/{id}", textHandler("user"))
+	r.GET("/", textHandler("home"))
+
+	w := serve(r, GET, "/users/?page=2")
+	assert.Eq(t, 301, w.Code)
+	assert.Eq(t, "/users?page=2", w.Header().Get("Location"))
+
+	w = serve(r, POST, "/users/7/")
+	assert.Eq(t, 308, w.Code)
+	assert.Eq(t, "/users/7", w.Header().Get("Location"))
+
+	assert.Eq(t, "users", serve(r, GET, "/users").Body.String())
+	assert.Eq(t, "home", serve(r, GET, "/").Body.String())
+	assert.Eq(t, 404, serve(r, GET, "/nope/").Code)
+	// Only routes of the request method count.
+	assert.Eq(t, 404, serve(r, GET, "/users/7/").Code)
+}
+
+func TestRedirectTrailingSlash_Strict(t *testing.T) {
+	r := New(StrictLastSlash, RedirectTrailingSlash)
+	r.GET("/dir/", textHandler("dir"))
+	r.GET("/file", textHandler("file"))
+	r.GET("/items/{id}/", textHandler("item"))
+
+	assert.Eq(t, "dir", serve(r, GET, "/dir/").Body.String())
+	assert.Eq(t, "item", serve(r, GET, "/items/1/").Body.String())
+	assert.Eq(t, "/dir/", serve(r, GET, "/dir").Header().Get("Location"))
+	assert.Eq(t, "/file", serve(r, GET, "/file/").Header().Get("Location"))
+	assert.Eq(t, "/items/1/", serve(r, GET, "/items/1").Header().Get("Location"))
+}
+
+func TestStrictLastSlash_DistinctRoutes(t *testing.T) {
+	r := New(StrictLastSlash)
+	r.GET("/users/", textHandler("slash"))
+	r.GET("/users", textHandler("plain"))
+
+	assert.Eq(t, "slash", serve(r, GET, "/users/").Body.String())
+	assert.Eq(t, "plain", serve(r, GET, "/users").Body.String())
+}
+
+func TestRedirectFixedPath(t *testing.T) {
+	r := New(RedirectFixedPath)
+	r.GET("/users/list", textHandler("list"))
+	r.GET("/Users/{Name}/posts", textHandler("posts"))
+	r.GET("/files/{id:\\d+}", textHandler("file"))
+	r.GET("/static/*path", textHandler("static"))
+	r.PUT("/items/{id}", textHandler("item"))
+
+	cases := map[string]string{
+		"/users/../users/list":       "/users/list",
+		"/users/./list":              "/users/list",
+		"/USERS/LIST":                "/users/list",
+		"/users//list":               "/users/list",
+		"/users/Tom/POSTS":           "/Users/Tom/posts",
+		"/FILES/42":                  "/files/42",
+		"/Static/CSS/App.css":        "/static/CSS/App.css",
+		"/a/../users/List?x=1":       "/users/list?x=1",
+		"/users/tom/../tom/Posts":    "/Users/tom/posts",
+		"/users/list/../list/./x/..": "/users/list",
+	}
+	for in, want := range cases {
+		w := serve(r, GET, in)
+		assert.Eq(t, 301, w.Code, in)
+		assert.Eq(t, want, w.Header().Get("Location"), in)
+	}
+
+	assert.Eq(t, 404, serve(r, GET, "/FILES/abc").Code)
+	assert.Eq(t, 404, serve(r, GET, "/nope").Code)
+
+	w := serve(r, PUT, "/ITEMS/9")
+	assert.Eq(t, 308, w.Code)
+	assert.Eq(t, "/items/9", w.Header().Get("Location"))
+}
+
+func TestRedirectFixedPath_TrailingSlashAndMount(t *testing.T) {
+	sub := New(RedirectFixedPath, RedirectTrailingSlash)
+	sub.GET("/Report", textHandler("report"))
+
+	r := New()
+	r.Mount("/admin", sub)
+
+	w := serve(r, GET, "/admin/report/")
+	assert.Eq(t, 301, w.Code)
+	assert.Eq(t, "/admin/Report", w.Header().Get("Location"))
+}
+
+func TestRadixTree_FindFold(t *testing.T) {
+	tree := newRadixTree()
+	for _, p := range []string{"/api/Users/:id", "/api/users/new", "/api/Items/*rest"} {
+		tree.insert(p, &Route{path: p})
+	}
+
+	fixed, ok := tree.findFold("/API/USERS/NEW")
+	assert.True(t, ok)
+	assert.Eq(t, "/api/users/new", fixed)
+
+	fixed, ok = tree.findFold("/api/users/AbC")
+	assert.True(t, ok)
+	assert.Eq(t, "/api/Users/AbC", fixed)
+
+	fixed, ok = tree.findFold("/api/items/A/b")
+	assert.True(t, ok)
+	assert.Eq(t, "/api/Items/A/b", fixed)
+
+	_, ok = tree.findFold("/api/other")
+	assert.False(t, ok)
+}
@@ -0,0 +1,283 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ResourceOptions configures a resource of ResourceWith.
+type ResourceOptions struct {
+	// Name is the path segment and route name prefix of the resource, the
+	// lower-cased controller type name by default.
+	Name string
+	// Param is the name of the member path param, "id" by default. It
+	// also prefixes the paths of nested resources: /users/{Param}/posts.
+	// A nested resource defaults to its singular name and "_id" if a
+	// parent has its param: /users/{id}/posts/{post_id}; its Shallow
+	// member routes keep "id": /posts/{id}.
+	Param string
+	// Only registers only these actions, Except all but these. Actions are
+	// named by controller method: "Index", "Show", "GetSearch", ...
+	Only   []string
+	Except []string
+	// APIOnly skips the Create and Edit actions, which serve HTML forms.
+	APIOnly bool
+	// Shallow registers the member routes of a nested resource below the
+	// parent's base path instead of the parent member: /posts/{id} rather
+	// than /users/{user_id}/posts/{id}. See Resource.Nested.
+	Shallow bool
+	// Middlewares run before the route middlewares of the resource.
+	Middlewares []HandlerFunc
+}
+
+// Resource is a resource registered by ResourceWith, to nest resources in.
+type Resource struct {
+	// scope is the group the resource base path is relative to.
+	scope *RouteGroup
+	// collection is the group of the collection routes.
+	collection *RouteGroup
+	name       string
+	param      string
+	// memberParam is the param of the member routes: param, unless a
+	// Shallow member route keeps the param renamed for its parents.
+	memberParam string
+	// params are the params of the resource paths: its parents' and its
+	// own.
+	params []string
+	// routeName prefixes the route names: parent names included.
+	routeName string
+	routes    []*Route
+}
+
+// restActions are the RESTful actions, in registration order.
+var restActions = []struct {
+	name   string
+	path   string
+	member bool
+}{
+	{IndexAction, "", false},
+	{CreateAction, "/create", false},
+	{StoreAction, "", false},
+	{ShowAction, "", true},
+	{EditAction, "/edit", true},
+	{UpdateAction, "", true},
+	{DeleteAction, "", true},
+}
+
+// actionVerbs are the method name prefixes of extra actions.
+var actionVerbs = []string{GET, POST, PUT, PATCH, DELETE}
+
+// ResourceWith registers RESTful routes for the controller struct in a
+// nested group, like Resource, configured by opts. It returns the
+// resource for nesting.
+//
+// Besides the RESTful actions, controller methods named <Verb><Name> or
+// <Verb>Member<Name>, with Verb one of Get, Post, Put, Patch and Delete,
+// register extra collection and member actions:
+//
+//	func (c *Users) GetSearch(ctx *rux.Context)         // GET  /users/search
+//	func (c *Users) PostMemberPublish(ctx *rux.Context) // POST /users/{id}/publish
+//
+// Their paths are the kebab-cased Name, their route names the resource
+// name and the snake-cased method name: users_get_search. Routes are
+// registered in a fixed order: the RESTful actions, then the extra
+// actions sorted by name.
+func (g *RouteGroup) ResourceWith(basePath string, controller any, opts ResourceOptions) *Resource {
+	scope := g.Group(basePath)
+	res := newResource(controller, opts)
+	res.scope = scope
+	res.routeName = res.name
+	res.collection = scope.Group("/"+res.name, opts.Middlewares...)
+	res.register(controller, opts, res.collection)
+	return res
+}
+
+// Nested registers the controller as a resource below the members of res:
+// /users/{user_id}/posts. Its routes are named after both resources:
+// users_posts_index. With opts.Shallow, the member routes are registered
+// below the base path of res instead: /posts/{id}.
+//
+// Nested panics if opts.Param is a param of res or its parents, as its
+// value would be shadowed.
+func (res *Resource) Nested(controller any, opts ResourceOptions) *Resource {
+	child := newResource(controller, opts)
+	if slices.Contains(res.params, child.param) {
+		if opts.Param != "" {
+			panic(fmt.Sprintf("rux: nested resource %s repeats the param %q of its parents, set another Param",
+				child.name, child.param))
+		}
+		child.param = strings.ReplaceAll(strings.TrimSuffix(child.name, "s"), "-", "_") + "_id"
+		if !opts.Shallow {
+			child.memberParam = child.param
+		}
+	}
+	child.params = append(slices.Clone(res.params), child.param)
+	child.scope = res.scope
+	child.routeName = res.routeName + "_" + child.name
+	child.collection = res.collection.Group("/{"+res.param+"}/"+child.name, opts.Middlewares...)
+	member := child.collection
+	if opts.Shallow {
+		member = res.scope.Group("/"+child.name, opts.Middlewares...)
+	}
+	child.register(controller, opts, member)
+	return child
+}
+
+// Routes returns the routes of the resource, nested resources excluded.
+func (res *Resource) Routes() []*Route { return res.routes }
+
+// newResource validates controller and applies the name options.
+func newResource(controller any, opts ResourceOptions) *Resource {
+	cv := reflect.ValueOf(controller)
+	if cv.Kind() != reflect.Ptr {
+		panic("rux: Resource controller must be a pointer")
+	}
+	if cv.Elem().Type().Kind() != reflect.Struct {
+		panic("rux: Resource controller must be a pointer to struct")
+	}
+	res := &Resource{name: opts.Name, param: opts.Param}
+	if res.name == "" {
+		res.name = strings.ToLower(cv.Elem().Type().Name())
+	}
+	if res.param == "" {
+		res.param = "id"
+	}
+	res.memberParam = res.param
+	res.params = []string{res.param}
+	return res
+}
+
+// register adds the actions of controller: collection actions in
+// res.collection, member actions in member.
+func (res *Resource) register(controller any, opts ResourceOptions, member *RouteGroup) {
+	cv := reflect.ValueOf(controller)
+	perActionMW := usesOf(cv)
+
+	add := func(action, path string, isMember bool, methods []string) {
+		if !opts.wants(action) {
+			return
+		}
+		handler, ok := cv.MethodByName(action).Interface().(func(*Context))
+		if !ok {
+			return
+		}
+		g := res.collection
+		if isMember {
+			g = member
+			path = "/{" + res.memberParam + "}" + path
+		}
+		if path == "" {
+			path = "/"
+		}
+		route := g.AddNamed(res.routeName+"_"+snakeCase(action), path, handler, methods...)
+		route.setHandlerName(methodName(cv.Type(), action))
+		if mws, ok := perActionMW[action]; ok {
+			route.Use(mws...)
+		}
+		res.routes = append(res.routes, route)
+	}
+
+	for _, a := range restActions {
+		if opts.APIOnly && (a.name == CreateAction || a.name == EditAction) {
+			continue
+		}
+		if cv.MethodByName(a.name).IsValid() {
+			add(a.name, a.path, a.member, RESTFulActions[a.name])
+		}
+	}
+
+	// Extra actions; reflect lists methods sorted by name.
+	ct := cv.Type()
+	for i := 0; i < ct.NumMethod(); i++ {
+		name := ct.Method(i).Name
+		verb, rest, isMember := parseActionName(name)
+		if verb == "" {
+			continue
+		}
+		add(name, "/"+kebabCase(rest), isMember, []string{verb})
+	}
+}
+
+// usesOf returns the per-action middlewares of the Uses() method of the
+// controller cv, if any.
+func usesOf(cv reflect.Value) map[string][]HandlerFunc {
+	if m := cv.MethodByName("Uses"); m.IsValid() {
+		if uses, ok := m.Interface().(func() map[string][]HandlerFunc); ok {
+			return uses()
+		}
+	}
+	return nil
+}
+
+// wants reports whether the Only and Except options keep action.
+func (o ResourceOptions) wants(action string) bool {
+	if len(o.Only) > 0 && !containsString(o.Only, action) {
+		return false
+	}
+	return !containsString(o.Except, action)
+}
+
+// parseActionName splits an extra action method name <Verb>[Member]<Name>.
+// verb is "" if name is not one.
+func parseActionName(name string) (verb, rest string, member bool) {
+	verb, rest = cutVerb(name)
+	if r, ok := strings.CutPrefix(rest, "Member"); ok && r != "" {
+		rest, member = r, true
+	}
+	if verb == "" || rest == "" {
+		return "", "", false
+	}
+	return verb, rest, member
+}
+
+// cutVerb splits a method name <Verb><Rest>, Verb one of actionVerbs in
+// CamelCase and Rest empty or starting with an upper case letter. verb
+// is "" if name does not start with a verb.
+func cutVerb(name string) (verb, rest string) {
+	for _, v := range actionVerbs {
+		prefix := v[:1] + strings.ToLower(v[1:])
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			if rest != "" && !isUpper(rest[0]) {
+				return "", ""
+			}
+			return v, rest
+		}
+	}
+	return "", ""
+}
+
+// kebabCase converts a CamelCase name to kebab-case: ResetPassword to
+// reset-password.
+func kebabCase(s string) string { return splitWords(s, '-') }
+
+// snakeCase converts a CamelCase name to snake_case.
+func snakeCase(s string) string { return splitWords(s, '_') }
+
+// splitWords lower-cases s, separating words with sep. An upper case run
+// is a single word: HTMLExport gives html-export.
+func splitWords(s string, sep byte) string {
+	return strings.ToLower(strings.Join(camelWords(s), string(sep)))
+}
+
+// camelWords splits a CamelCase name into its words: UserByID gives User,
+// By, ID.
+func camelWords(s string) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(s); i++ {
+		if isUpper(s[i]) && (isLowerOrDigit(s[i-1]) || isUpper(s[i-1]) && i+1 < len(s) && isLowerOrDigit(s[i+1])) {
+			words = append(words, s[start:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+func isLowerOrDigit(c byte) bool { return 'a' <= c && c <= 'z' || '0' <= c && c <= '9' }
+
+func isUpper(c byte) bool { return 'A' <= c && c <= 'Z' }
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+type userRes struct{}
+
+func (*userRes) Index(c *Context)  { c.Text(200, "users") }
+func (*userRes) Create(c *Context) { c.Text(200, "new user form") }
+func (*userRes) Store(c *Context)  { c.Text(200, "store user") }
+func (*userRes) Show(c *Context)   { c.Text(200, "user "+c.Param("user_id")) }
+func (*userRes) Edit(c *Context)   { c.Text(200, "edit user") }
+func (*userRes) Update(c *Context) { c.Text(200, "update user") }
+func (*userRes) Delete(c *Context) { c.Text(200, "delete user") }
+
+func (*userRes) GetSearch(c *Context)               { c.Text(200, "search users") }
+func (*userRes) PostMemberResetPassword(c *Context) { c.Text(200, "reset "+c.Param("user_id")) }
+
+// Getter is not an action: no upper case letter after the verb.
+func (*userRes) Getter(c *Context) {}
+
+type postRes struct{}
+
+func (*postRes) Index(c *Context) { c.Text(200, "posts of "+c.Param("user_id")) }
+func (*postRes) Store(c *Context) { c.Text(200, "store post of "+c.Param("user_id")) }
+func (*postRes) Show(c *Context)  { c.Text(200, "post "+c.Param("id")) }
+
+func TestRouter_ResourceWith(t *testing.T) {
+	r := New()
+	res := r.ResourceWith("/api", &userRes{}, ResourceOptions{Name: "users", Param: "user_id", APIOnly: true})
+
+	var names, paths []string
+	for _, route := range res.Routes() {
+		names = append(names, route.Name())
+		paths = append(paths, route.MethodString(",")+" "+route.Path())
+	}
+	assert.Eq(t, []string{
+		"users_index", "users_store", "users_show", "users_update", "users_delete",
+		"users_get_search", "users_post_member_reset_password",
+	}, names)
+	assert.Eq(t, []string{
+		"GET /api/users",
+		"POST /api/users",
+		"GET /api/users/:user_id",
+		"PUT,PATCH /api/users/:user_id",
+		"DELETE /api/users/:user_id",
+		"GET /api/users/search",
+		"POST /api/users/:user_id/reset-password",
+	}, paths)
+
+	assert.Eq(t, "user 7", serve(r, GET, "/api/users/7").Body.String())
+	assert.Eq(t, "search users", serve(r, GET, "/api/users/search").Body.String())
+	assert.Eq(t, "reset 7", serve(r, POST, "/api/users/7/reset-password").Body.String())
+	assert.Eq(t, "user create", serve(r, GET, "/api/users/create").Body.String())
+	assert.Eq(t, 404, serve(r, GET, "/api/users/7/edit").Code)
+	assert.Eq(t, "/api/users/7", r.BuildURL("users_show", "{user_id}", 7).String())
+}
+
+func TestRouter_ResourceWith_OnlyExcept(t *testing.T) {
+	r := New()
+	only := r.ResourceWith("/", &userRes{}, ResourceOptions{Name: "a", Only: []string{IndexAction, "GetSearch"}})
+	except := r.ResourceWith("/", &userRes{}, ResourceOptions{Name: "b", Param: "user_id", Except: []string{DeleteAction, "GetSearch"}})
+
+	assert.Len(t, only.Routes(), 2)
+	assert.Eq(t, "a_get_search", only.Routes()[1].Name())
+	assert.Len(t, except.Routes(), 7)
+	assert.Eq(t, 404, serve(r, GET, "/a/1").Code)
+	assert.Eq(t, 404, serve(r, DELETE, "/b/1").Code)
+	assert.Eq(t, "user search", serve(r, GET, "/b/search").Body.String())
+}
+
+func TestResource_Nested(t *testing.T) {
+	r := New()
+	users := r.ResourceWith("/", &userRes{}, ResourceOptions{Name: "users", Param: "user_id"})
+	posts := users.Nested(&postRes{}, ResourceOptions{Name: "posts"})
+	assert.Eq(t, "users_posts_index", posts.Routes()[0].Name())
+
+	assert.Eq(t, "posts of 3", serve(r, GET, "/users/3/posts").Body.String())
+	assert.Eq(t, "store post of 3", serve(r, POST, "/users/3/posts").Body.String())
+	assert.Eq(t, "post 9", serve(r, GET, "/users/3/posts/9").Body.String())
+	assert.Eq(t, "/users/3/posts/9", r.BuildURL("users_posts_show", "{user_id}", 3, "{id}", 9).String())
+
+	r = New()
+	users = r.ResourceWith("/api", &userRes{}, ResourceOptions{Name: "users", Param: "user_id"})
+	users.Nested(&postRes{}, ResourceOptions{Name: "posts", Shallow: true})
+	assert.Eq(t, "posts of 3", serve(r, GET, "/api/users/3/posts").Body.String())
+	assert.Eq(t, "post 9", serve(r, GET, "/api/posts/9").Body.String())
+	assert.Eq(t, 404, serve(r, GET, "/api/users/3/posts/9").Code)
+}
+
+func TestResource_Nested_ShallowParams(t *testing.T) {
+	r := New()
+	posts := r.ResourceWith("/", &postRes{}, ResourceOptions{Name: "posts"})
+	comments := posts.Nested(&commentRes{}, ResourceOptions{Name: "comments", Shallow: true})
+	// Resources nested in a shallow one still get a param of their own.
+	likes := comments.Nested(&commentRes{}, ResourceOptions{Name: "likes"})
+	assert.Eq(t, "/comments/:id", comments.Routes()[0].Path())
+	assert.Eq(t, "/posts/:id/comments/:comment_id/likes/:like_id", likes.Routes()[0].Path())
+	assert.Eq(t, "9 ", serve(r, GET, "/comments/9").Body.String())
+}
+
+type commentRes struct{}
+
+func (*commentRes) Show(c *Context) { c.Text(200, c.Param("id")+" "+c.Param("comment_id")) }
+
+func TestResource_Nested_DefaultParams(t *testing.T) {
+	r := New()
+	posts := r.ResourceWith("/", &postRes{}, ResourceOptions{Name: "posts"})
+	comments := posts.Nested(&commentRes{}, ResourceOptions{Name: "comments"})
+	assert.Eq(t, "/posts/:id/comments/:comment_id", comments.Routes()[0].Path())
+	assert.Eq(t, "post 3", serve(r, GET, "/posts/3").Body.String())
+	assert.Eq(t, "3 9", serve(r, GET, "/posts/3/comments/9").Body.String())
+
+	users := New().ResourceWith("/", &userRes{}, ResourceOptions{Name: "users", Param: "user_id"})
+	assert.PanicsMsg(t, func() {
+		users.Nested(&postRes{}, ResourceOptions{Name: "posts", Param: "user_id"})
+	}, `rux: nested resource posts repeats the param "user_id" of its parents, set another Param`)
+}
+
+func TestResource_Naming(t *testing.T) {
+	assert.Eq(t, "reset-password", kebabCase("ResetPassword"))
+	assert.Eq(t, "html-export", kebabCase("HTMLExport"))
+	assert.Eq(t, "export-html", kebabCase("ExportHTML"))
+	assert.Eq(t, "get_v2_items", snakeCase("GetV2Items"))
+
+	verb, name, member := parseActionName("PatchMemberArchive")
+	assert.Eq(t, PATCH, verb)
+	assert.Eq(t, "Archive", name)
+	assert.True(t, member)
+	verb, name, member = parseActionName("GetMember")
+	assert.Eq(t, GET, verb)
+	assert.Eq(t, "Member", name)
+	assert.False(t, member)
+	verb, _, _ = parseActionName("Getter")
+	assert.Eq(t, "", verb)
+	verb, _, _ = parseActionName("Show")
+	assert.Eq(t, "", verb)
+}
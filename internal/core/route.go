@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"net/url"
+	"reflect"
 	"strings"
 
 	"github.com/gookit/goutil"
@@ -24,7 +25,23 @@ type RouteInfo struct {
 	Path        string
 	HandlerName string
 	Methods     []string
-	HandlerNum  int
+	// HandlerNum is the number of middlewares in the effective chain.
+	HandlerNum int
+	// Middlewares are the names of the effective middlewares, in run order:
+	// global, group and route middlewares.
+	Middlewares []string
+	// Host is the host pattern the route is bound to ("" for any host).
+	Host string
+	// Constraints maps constrained param names to their regex ({id:\d+}).
+	Constraints map[string]string
+	// Conditions describe the request conditions set by Router.When.
+	Conditions []string
+	// Version is the API version of a route of Router.Versions.
+	Version string
+	// Variant and Weight are the variant name and weight set by
+	// Router.Variant.
+	Variant string
+	Weight  int
 }
 
 // Route describes a single registered route.
@@ -40,10 +57,45 @@ type Route struct {
 	// URL-building. path is rewritten to colon syntax at registration time.
 	originalPath string
 
+	// constraints maps param name -> regex for constrained params.
+	constraints map[string]string
+
+	// host is the host pattern set by Router.Host, "" for any host.
+	host string
+
+	// mounted is the sub-router of a Mount route.
+	mounted *Router
+
+	// expansions are the per-path copies registered for a route with an
+	// optional segment. Their chains are synced on Router.Freeze.
+	expansions []*Route
+
 	chain      HandlersChain
 	finalChain HandlersChain
 
+	// group is the group the route was registered in.
+	group *RouteGroup
+	// hooks are the route's own error and panic handlers.
+	hooks hookSet
+
+	// conds are the request conditions set by Router.When.
+	conds []Condition
+	// version is the API version of a route of a version group.
+	version string
+	// variant is the weighted variant set by Router.Variant.
+	variant *routeVariant
+	// variants are the routes sharing the method and path of a slot
+	// route, which the tables store in their place (see addVariant).
+	variants []*Route
+
+	// Opts is the route's own metadata, see With and Meta.
 	Opts map[string]any
+
+	// io are the request and response types of a Typed handler.
+	io *typedIOTypes
+	// handlerName names the handler when its func name does not: the
+	// method of a reflected controller or the func of a Typed handler.
+	handlerName string
 }
 
 // newRoute creates a Route with the main handler appended to chain.
@@ -57,7 +109,6 @@ func newRoute(path string, handler HandlerFunc, methods []string) *Route {
 	} else {
 		methods = formatMethods(methods)
 	}
-	validateMethods(methods)
 	return &Route{
 		path:    simpleFmtPath(path),
 		methods: methods,
@@ -97,6 +148,12 @@ func (r *Route) Path() string { return r.path }
 // Methods returns the route's allowed methods.
 func (r *Route) Methods() []string { return r.methods }
 
+// Host returns the host pattern the route is bound to, or "" for any host.
+func (r *Route) Host() string { return r.host }
+
+// Constraints returns the regex constraint of each constrained param, or nil.
+func (r *Route) Constraints() map[string]string { return r.constraints }
+
 // MethodString joins allowed methods with sep.
 func (r *Route) MethodString(sep string) string { return strings.Join(r.methods, sep) }
 
@@ -116,26 +173,108 @@ func (r *Route) Handlers() HandlersChain {
 	return r.chain[:len(r.chain)-1]
 }
 
-// HandlerName returns the symbolic name of the main handler.
+// HandlerName returns the symbolic name of the main handler: its func
+// name, pkg.(*T).Method for a controller method.
 func (r *Route) HandlerName() string {
-	return goutil.FuncName(r.Handler())
+	if r.handlerName != "" {
+		return r.handlerName
+	}
+	return funcName(r.Handler())
+}
+
+// setHandlerName sets the HandlerName of r and its optional-segment
+// copies.
+func (r *Route) setHandlerName(name string) {
+	r.handlerName = name
+	for _, exp := range r.expansions {
+		exp.handlerName = name
+	}
+}
+
+// funcName returns the name of the func h, method values named after
+// their method: pkg.(*T).Method.
+func funcName(h HandlerFunc) string {
+	return strings.TrimSuffix(goutil.FuncName(h), "-fm")
+}
+
+// methodName returns the name of the method of the controller type ct,
+// as funcName names its method values.
+func methodName(ct reflect.Type, method string) string {
+	if ct.Kind() == reflect.Pointer {
+		return ct.Elem().PkgPath() + ".(*" + ct.Elem().Name() + ")." + method
+	}
+	return ct.PkgPath() + "." + ct.Name() + "." + method
+}
+
+// effectiveChain returns the chain dispatched for the route: finalChain
+// once frozen, otherwise the route chain (global middlewares unknown).
+func (r *Route) effectiveChain() HandlersChain {
+	if r.finalChain != nil {
+		return r.finalChain
+	}
+	return r.chain
 }
 
 // String returns a debug representation of the route.
 func (r *Route) String() string {
-	return fmt.Sprintf("%-15s %-38s --> %s (%d middleware)",
-		r.MethodString(","), r.path, r.HandlerName(), len(r.chain)-1)
+	return r.format(r.host+r.path, r.effectiveChain())
+}
+
+// format renders a route dump line for path with the given effective chain.
+func (r *Route) format(path string, chain HandlersChain) string {
+	line := fmt.Sprintf("%-15s %-38s --> %s (%d middleware", r.MethodString(","), path, r.HandlerName(), len(chain)-1)
+	if len(chain) > 1 {
+		line += ": " + strings.Join(handlerNames(chain[:len(chain)-1]), ", ")
+	}
+	line += ")"
+	if len(r.conds) > 0 {
+		line += " when " + strings.Join(condStrings(r.conds), ", ")
+	}
+	if r.variant != nil {
+		line += " variant " + r.variantString()
+	}
+	return line
 }
 
 // Info returns a RouteInfo snapshot.
 func (r *Route) Info() RouteInfo {
-	return RouteInfo{r.name, r.path, r.HandlerName(), r.methods, len(r.chain) - 1}
+	return r.info(r.effectiveChain())
 }
 
-// validateMethods panics if any method in m is not a recognized HTTP verb.
-func validateMethods(m []string) {
+// info returns a RouteInfo snapshot with the given effective chain.
+func (r *Route) info(chain HandlersChain) RouteInfo {
+	ri := RouteInfo{
+		Name:        r.name,
+		Path:        r.path,
+		HandlerName: r.HandlerName(),
+		Methods:     r.methods,
+		HandlerNum:  len(chain) - 1,
+		Constraints: r.constraints,
+		Host:        r.host,
+		Conditions:  condStrings(r.conds),
+		Version:     r.version,
+		Variant:     r.Variant(),
+		Weight:      r.Weight(),
+	}
+	if len(chain) > 1 {
+		ri.Middlewares = handlerNames(chain[:len(chain)-1])
+	}
+	return ri
+}
+
+// handlerNames returns the symbolic names of handlers.
+func handlerNames(handlers HandlersChain) []string {
+	names := make([]string, len(handlers))
+	for i, h := range handlers {
+		names[i] = funcName(h)
+	}
+	return names
+}
+
+// validateMethods panics if any method in m is not in ms.
+func validateMethods(ms *methodSet, m []string) {
 	for _, method := range m {
-		if methodIndex(method) < 0 {
+		if ms.index(method) < 0 {
 			goutil.Panicf("rux: invalid HTTP method %q", method)
 		}
 	}
@@ -176,6 +315,10 @@ func simpleFmtPath(path string) string {
 // Path params are expressed using the v1 placeholder form (e.g. "{id}").
 // originalPath is used so the {name} placeholders are still present even
 // though the registered path has been rewritten to colon syntax.
+//
+// For host-bound routes the host pattern fills the URL host (host params
+// use the same "{name}" keys) unless the builder already sets a host.
+// Wildcard host patterns cannot be built and are left out.
 func (r *Route) ToURL(buildArgs ...any) *url.URL {
 	pathTpl := r.originalPath
 	if pathTpl == "" {
@@ -184,7 +327,7 @@ func (r *Route) ToURL(buildArgs ...any) *url.URL {
 
 	n := len(buildArgs)
 	if n == 0 {
-		return NewBuildRequestURL().Path(pathTpl).Build()
+		return NewBuildRequestURL().Path(pathTpl).hostTpl(r.host).Build()
 	}
 
 	var URLBuilder *BuildRequestURL
@@ -210,5 +353,5 @@ func (r *Route) ToURL(buildArgs ...any) *url.URL {
 		URLBuilder = NewBuildRequestURL()
 	}
 
-	return URLBuilder.Path(pathTpl).Build(withParams)
+	return URLBuilder.Path(pathTpl).hostTpl(r.host).Build(withParams)
 }
@@ -2,9 +2,10 @@ package core
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
-	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -23,36 +24,76 @@ import (
 type Router struct {
 	Name string
 
-	// Per-method static routes. Indexed by methodIndex().
-	// nil for methods that have no static routes registered yet.
-	staticRoutes [methodCount]map[string]*Route
+	// Host-less per-method static maps and radix trees.
+	routeTable
 
-	// Per-method dynamic radix trees. nil if no dynamic routes for that method.
-	dynamicTrees [methodCount]*radixTree
+	// Host-bound route tables (see Host). hasHosts is checked first so
+	// host-less routers skip host resolution entirely.
+	hasHosts     bool
+	exactHosts   map[string]*hostRoutes
+	patternHosts []*hostRoutes
 
 	namedRoutes map[string]*Route
 	routeList   []*Route
 
 	// Global middleware. Frozen into each route's finalChain on Freeze().
 	globalChain HandlersChain
+	// scopedChain is added by UseFromNow. It is merged into route chains
+	// at registration, so it only covers routes registered afterwards.
+	scopedChain HandlersChain
 
-	currentGroupPrefix   string
-	currentGroupHandlers HandlersChain
+	// curGroup is the group of the enclosing closure Group / Host call.
+	curGroup *RouteGroup
+	// groupNoRoutes are groups with a NotFound chain, longest prefix first.
+	groupNoRoutes []*RouteGroup
 
 	noRoute   HandlersChain
 	noAllowed HandlersChain
+	// optionsChain answers OPTIONS requests for HandleOptions.
+	optionsChain HandlersChain
+
+	// Sub-routers attached via Mount; frozen together with this router.
+	mounts []*Router
+	// versionSets are the API version sets, registered on Freeze.
+	versionSets []*APIVersions
 
 	// Settings.
-	OnError                HandlerFunc
-	OnPanic                HandlerFunc
+	// OnError runs when handlers recorded errors; ProblemHandler if nil.
+	// OnPanic runs with the recovered panic (see Context.Recovered); if
+	// nil, panics propagate. Groups and routes can override both.
+	OnError HandlerFunc
+	OnPanic HandlerFunc
+	// MapBindError and MapValidationError convert binding errors for
+	// ToHTTPError. BindErrorMapper and ValidationErrorMapper if nil.
+	MapBindError       func(err error) *HTTPError
+	MapValidationError func(err error) *HTTPError
+
 	interceptAll           string
 	useEncodedPath         bool
 	strictLastSlash        bool
+	redirectTrailingSlash  bool
+	redirectFixedPath      bool
 	handleMethodNotAllowed bool
+	handleOptions          bool
 	handleFallbackRoute    bool
-
-	frozen  atomic.Bool
-	counter int
+	// paramsLimit is set by ParamsLimit, 0 means MaxParams.
+	paramsLimit int
+	// maxParams is the largest param count of a route, set on Freeze to
+	// size the params of pooled Contexts.
+	maxParams int
+
+	frozen     atomic.Bool
+	freezeOnce sync.Once
+	counter    int
+	// mu serializes route registration.
+	mu sync.Mutex
+	// methods are the methods known to r, see RegisterMethod.
+	methods *methodSet
+
+	// opts are the options passed to New, reused by NewRouteSet.
+	opts []func(*Router)
+	// active is the route set installed by Swap; nil means r's own routes.
+	active atomic.Pointer[Router]
 
 	ctxPool sync.Pool
 
@@ -64,12 +105,14 @@ func New(opts ...func(*Router)) *Router {
 	r := &Router{
 		Name:        "default",
 		namedRoutes: make(map[string]*Route),
+		opts:        opts,
+		methods:     stdMethods,
 	}
 	for _, opt := range opts {
 		opt(r)
 	}
 	r.ctxPool.New = func() any {
-		return &Context{index: -1, router: r}
+		return &Context{index: -1, router: r, params: newParams(r.maxParams)}
 	}
 	return r
 }
@@ -102,54 +145,62 @@ func InterceptAll(path string) func(*Router) {
 
 // Freeze marks the router read-only. Subsequent registration calls panic.
 // It merges globalChain into each route's finalChain and mirrors GET routes
-// onto HEAD. Idempotent — safe to call multiple times.
+// onto HEAD. Idempotent — safe to call multiple times, also concurrently:
+// callers block until the first call has finished. Registration calls
+// running concurrently with it take effect before it or panic.
 func (r *Router) Freeze() {
-	if !r.frozen.CompareAndSwap(false, true) {
+	r.freezeOnce.Do(r.freeze)
+}
+
+func (r *Router) freeze() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.frozen.Load() {
 		return
 	}
+	for _, vs := range r.versionSets {
+		vs.register()
+	}
 	for _, route := range r.routeList {
 		if len(r.globalChain) == 0 {
 			route.finalChain = route.chain
-			continue
+		} else {
+			merged := make(HandlersChain, 0, len(r.globalChain)+len(route.chain))
+			merged = append(merged, r.globalChain...)
+			merged = append(merged, route.chain...)
+			route.finalChain = merged
+		}
+		// Optional-segment expansions are copies taken at registration;
+		// sync chains added later via Route.Use, and metadata.
+		for _, exp := range route.expansions {
+			exp.chain = route.chain
+			exp.finalChain = route.finalChain
+			exp.Opts = route.Opts
 		}
-		merged := make(HandlersChain, 0, len(r.globalChain)+len(route.chain))
-		merged = append(merged, r.globalChain...)
-		merged = append(merged, route.chain...)
-		route.finalChain = merged
 	}
 	r.mirrorGetToHead()
+	r.maxParams = r.computeMaxParams()
+	if r.handleOptions {
+		r.routeTable.buildAllow(r.methods)
+	}
+	for _, sub := range r.mounts {
+		sub.Freeze()
+	}
+	if debug {
+		r.warnIssues()
+	}
+	// Published last, so lock-free readers of frozen see finished chains.
+	r.frozen.Store(true)
 }
 
-// mirrorGetToHead copies every GET route to HEAD unless an explicit HEAD
-// route already exists at that path. Required by P-9.
+// mirrorGetToHead mirrors GET routes onto HEAD in every route table.
 func (r *Router) mirrorGetToHead() {
-	getIdx := methodIndex(GET)
-	headIdx := methodIndex(HEAD)
-
-	// Static.
-	if getStatic := r.staticRoutes[getIdx]; getStatic != nil {
-		if r.staticRoutes[headIdx] == nil {
-			r.staticRoutes[headIdx] = make(map[string]*Route, len(getStatic))
-		}
-		head := r.staticRoutes[headIdx]
-		for path, route := range getStatic {
-			if _, exists := head[path]; !exists {
-				head[path] = route
-			}
-		}
+	r.routeTable.mirrorGetToHead()
+	for _, h := range r.exactHosts {
+		h.mirrorGetToHead()
 	}
-
-	// Dynamic.
-	if get := r.dynamicTrees[getIdx]; get != nil {
-		if r.dynamicTrees[headIdx] == nil {
-			r.dynamicTrees[headIdx] = newRadixTree()
-		}
-		head := r.dynamicTrees[headIdx]
-		get.walk(func(path string, leaf *node) {
-			if !head.hasExact(path) {
-				head.insert(path, leaf.route)
-			}
-		})
+	for _, h := range r.patternHosts {
+		h.mirrorGetToHead()
 	}
 }
 
@@ -171,7 +222,7 @@ func (r *Router) AddNamed(name, path string, handler HandlerFunc, methods ...str
 
 // AddRoute registers a pre-constructed Route.
 func (r *Router) AddRoute(route *Route) *Route {
-	r.appendRoute(route)
+	r.appendRoute(route, r.group())
 	return route
 }
 
@@ -205,23 +256,41 @@ func (r *Router) TRACE(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
 	return r.Add(path, h, TRACE).Use(mw...)
 }
 
-// anyMethods is the canonical order used by Any.
-var anyMethods = []string{GET, POST, PUT, PATCH, DELETE, OPTIONS, HEAD, CONNECT, TRACE}
-
 // Any registers a route on every supported HTTP method.
 func (r *Router) Any(path string, h HandlerFunc, mw ...HandlerFunc) *Route {
-	return r.Add(path, h, anyMethods...).Use(mw...)
+	return r.Add(path, h, r.AnyMethods()...).Use(mw...)
+}
+
+// lockUnfrozen locks r.mu for a registration call, or panics with msg
+// once r is frozen. The check is done under the lock, as Freeze holds it.
+func (r *Router) lockUnfrozen(msg string) {
+	r.mu.Lock()
+	if r.frozen.Load() {
+		r.mu.Unlock()
+		panic(msg)
+	}
 }
 
 // appendRoute formats the path, applies group context, expands optional
 // segments, and dispatches to static or dynamic storage.
-func (r *Router) appendRoute(route *Route) {
-	if r.frozen.Load() {
-		panic("rux: cannot add route after router is frozen")
+func (r *Router) appendRoute(route *Route, g *RouteGroup) {
+	r.lockUnfrozen("rux: cannot add route after router is frozen")
+	defer r.mu.Unlock()
+
+	if g.version != nil {
+		// Version routes are added on Freeze, see APIVersions.
+		g.version.add(route, g)
+		return
 	}
+	r.addRoute(route, g, r.scopedChain)
+}
 
+// addRoute applies the group g and the UseFromNow middlewares scoped to
+// route and stores it. Callers hold r.mu.
+func (r *Router) addRoute(route *Route, g *RouteGroup, scoped HandlersChain) {
+	validateMethods(r.methods, route.methods)
 	// Apply group prefix and middlewares before dispatch.
-	r.applyGroup(route)
+	r.applyGroup(route, g, scoped)
 
 	if route.name != "" {
 		r.namedRoutes[route.name] = route
@@ -231,6 +300,7 @@ func (r *Router) appendRoute(route *Route) {
 	// Preserve the registered (group-prefixed) path with {name} placeholders
 	// so Route.ToURL can substitute them at URL-build time.
 	route.originalPath = route.path
+	route.constraints = parseConstraints(route.path)
 
 	// Expand optional segments into one or more concrete paths.
 	if hasOptionalSegment(route.path) {
@@ -238,62 +308,90 @@ func (r *Router) appendRoute(route *Route) {
 		r.counter++ // count as one user-defined route regardless of expansion
 		for _, expandedPath := range parseOptionalSegments(route.path) {
 			expandedRoute := *route
-			expandedRoute.path = normalizePath(expandedPath)
+			expandedRoute.path = r.routePath(expandedPath)
 			r.registerSingleRoute(&expandedRoute)
+			route.expansions = append(route.expansions, &expandedRoute)
 		}
 		return
 	}
 
 	r.counter++
 	// Convert {id} -> :id syntax so static-vs-dynamic detection is consistent
-	// with the tree's expected format.
-	route.path = normalizePath(convertParamSyntax(route.path))
+	// with the tree's expected format. Constrained params keep their
+	// {id:\d+} form; the tree compiles and enforces the regex.
+	route.path = r.routePath(convertParamSyntax(route.path))
 	r.registerSingleRoute(route)
 }
 
-// registerSingleRoute stores route in the right per-method bucket.
+// registerSingleRoute checks the host + path param count of route and
+// stores it in the table of its host (or the host-less table).
 func (r *Router) registerSingleRoute(route *Route) {
-	if isStaticPath(route.path) {
-		for _, m := range route.methods {
-			idx := methodIndex(m)
-			if idx < 0 {
-				panic("rux: unknown method " + m)
-			}
-			if r.staticRoutes[idx] == nil {
-				r.staticRoutes[idx] = make(map[string]*Route, 4)
-			}
-			if _, dup := r.staticRoutes[idx][route.path]; dup {
-				panic("rux: duplicate static route: " + m + " " + route.path)
-			}
-			r.staticRoutes[idx][route.path] = route
+	n := countParams(route.path)
+	var h *hostRoutes
+	if route.host != "" {
+		h = r.hostRoutes(route.host)
+		n += h.params
+	}
+	if limit := r.maxParamsPerRoute(); n > limit {
+		where := route.path
+		if h != nil {
+			where = h.pattern + where
 		}
+		panic(fmt.Sprintf("rux: route %s %s has %d path params, more than the limit of %d (see ParamsLimit)",
+			route.MethodString(","), where, n, limit))
+	}
+
+	if h == nil {
+		r.routeTable.register(route, r.methods)
 		return
 	}
+	h.register(route, r.methods)
+}
 
-	for _, m := range route.methods {
-		idx := methodIndex(m)
-		if idx < 0 {
-			panic("rux: unknown method " + m)
-		}
-		if r.dynamicTrees[idx] == nil {
-			r.dynamicTrees[idx] = newRadixTree()
-		}
-		r.dynamicTrees[idx].insert(route.path, route)
+// maxParamsPerRoute returns the ParamsLimit of r.
+func (r *Router) maxParamsPerRoute() int {
+	if r.paramsLimit > 0 {
+		return r.paramsLimit
+	}
+	return MaxParams
+}
+
+// computeMaxParams returns the largest host + path param count of the
+// routes of r, to size the params of its Contexts.
+func (r *Router) computeMaxParams() int {
+	n := r.routeTable.maxParams()
+	for _, h := range r.exactHosts {
+		n = max(n, h.params+h.maxParams())
+	}
+	for _, h := range r.patternHosts {
+		n = max(n, h.params+h.maxParams())
 	}
+	return n
 }
 
-// applyGroup merges current group prefix and handlers into the route.
-func (r *Router) applyGroup(route *Route) {
+// applyGroup merges the group prefix, host, name prefix, and the scoped
+// and group handlers into the route.
+func (r *Router) applyGroup(route *Route, g *RouteGroup, scoped HandlersChain) {
 	routePath := r.formatPath(route.path)
-	if r.currentGroupPrefix != "" {
-		routePath = r.formatPath(r.currentGroupPrefix + routePath)
+	if g.prefix != "" {
+		routePath = r.formatPath(g.prefix + routePath)
 	}
 	route.path = routePath
+	route.group = g
+	route.conds = g.conds
+	route.variant = g.variant
+	if g.host != "" && route.host == "" {
+		route.host = g.host
+	}
+	if route.name != "" {
+		route.name = g.namePrefix + route.name
+	}
 
-	if len(r.currentGroupHandlers) > 0 {
-		// Group middlewares run before route's own middlewares.
-		merged := make(HandlersChain, 0, len(r.currentGroupHandlers)+len(route.chain))
-		merged = append(merged, r.currentGroupHandlers...)
+	if handlers := g.chain(); len(handlers)+len(scoped) > 0 {
+		// Scoped then group middlewares run before route's own middlewares.
+		merged := make(HandlersChain, 0, len(scoped)+len(handlers)+len(route.chain))
+		merged = append(merged, scoped...)
+		merged = append(merged, handlers...)
 		merged = append(merged, route.chain...)
 		route.chain = merged
 	}
@@ -306,36 +404,37 @@ func (r *Router) applyGroup(route *Route) {
 // Group registers all routes added inside fn under the given path prefix.
 // Middlewares supplied as middles run before route-level middlewares.
 // Nested Group calls compose: prefixes concatenate, handlers stack.
+//
+// Group is a wrapper around NewGroup for the closure style; it changes
+// the group of r while fn runs, so it must not be used concurrently. Use
+// NewGroup to build groups from several goroutines.
 func (r *Router) Group(prefix string, fn func(), middles ...HandlerFunc) {
-	prevPrefix := r.currentGroupPrefix
-	r.currentGroupPrefix = prevPrefix + r.formatPath(prefix)
-
-	prevHandlers := r.currentGroupHandlers
-	if len(middles) > 0 {
-		if len(prevHandlers) > 0 {
-			// Create a fresh slice to avoid aliasing prevHandlers's backing array.
-			r.currentGroupHandlers = append(append(HandlersChain{}, prevHandlers...), middles...)
-		} else {
-			r.currentGroupHandlers = middles
-		}
-	}
-
-	fn()
-
-	r.currentGroupPrefix = prevPrefix
-	r.currentGroupHandlers = prevHandlers
+	r.withGroup(r.NewGroup(prefix, middles...), fn)
 }
 
-// Use appends global middleware. Per Q6 of the design, Use must be called
-// before any route registration — calling it later panics.
+// Use appends global middleware. It can be called at any time before
+// Freeze and applies to every route, including routes registered before
+// the call: the global chain is merged into each route on Freeze and runs
+// before group and route middlewares. See UseFromNow for middleware that
+// only applies to routes registered later.
 func (r *Router) Use(handlers ...HandlerFunc) {
-	if r.frozen.Load() {
-		panic("rux: cannot Use after router is frozen")
-	}
-	if len(r.routeList) > 0 {
-		panic("rux: Use must be called before any route registration (Q6)")
-	}
+	r.lockUnfrozen("rux: cannot Use after router is frozen")
 	r.globalChain = append(r.globalChain, handlers...)
+	r.mu.Unlock()
+}
+
+// UseFromNow appends middleware that only applies to routes registered
+// after the call, in any group. It runs after the global middlewares and
+// before group middlewares.
+//
+//	r.GET("/health", health)    // no auth
+//	r.UseFromNow(auth)
+//	r.GET("/account", account)  // auth
+func (r *Router) UseFromNow(handlers ...HandlerFunc) {
+	r.lockUnfrozen("rux: cannot Use after router is frozen")
+	// Create a fresh slice: registered routes keep the previous chain.
+	r.scopedChain = append(append(HandlersChain{}, r.scopedChain...), handlers...)
+	r.mu.Unlock()
 }
 
 // NotFound sets the handlers chain for unmatched routes (404).
@@ -363,6 +462,14 @@ type ControllerFace interface {
 	AddRoutes(g *Router)
 }
 
+// GroupController is implemented by controllers registering their routes
+// on the group Router.Controller and RouteGroup.Controller give them.
+// Unlike ControllerFace, it leaves the router state alone, so it may be
+// registered concurrently with other routes.
+type GroupController interface {
+	AddGroupRoutes(g *RouteGroup)
+}
+
 // RESTful action names, mapped to default HTTP methods.
 const (
 	IndexAction  = "Index"
@@ -385,12 +492,11 @@ var RESTFulActions = map[string][]string{
 	DeleteAction: {DELETE},
 }
 
-// Controller registers all routes from the controller's AddRoutes method
-// under the given basePath, with shared middlewares.
-func (r *Router) Controller(basePath string, c ControllerFace, middles ...HandlerFunc) {
-	r.Group(basePath, func() {
-		c.AddRoutes(r)
-	}, middles...)
+// Controller registers all routes of the controller, a GroupController or
+// ControllerFace, under the given basePath, with shared middlewares. See
+// RouteGroup.Controller.
+func (r *Router) Controller(basePath string, c any, middles ...HandlerFunc) {
+	r.group().Controller(basePath, c, middles...)
 }
 
 // Resource registers RESTful routes for the given controller struct.
@@ -405,56 +511,17 @@ func (r *Router) Controller(basePath string, c ControllerFace, middles ...Handle
 //	DELETE      /resource/{id}       Delete   resource_delete
 //
 // If the controller has a Uses() map[string][]HandlerFunc method, per-action
-// middlewares are wired automatically.
+// middlewares are wired automatically. Route names are the resource name
+// and the snake-cased action. See ResourceWith for options, nesting and
+// extra actions.
 func (r *Router) Resource(basePath string, controller any, middles ...HandlerFunc) {
-	cv := reflect.ValueOf(controller)
-	if cv.Kind() != reflect.Ptr {
-		panic("rux: Resource controller must be a pointer")
-	}
-	ct := cv.Type()
-	if cv.Elem().Type().Kind() != reflect.Struct {
-		panic("rux: Resource controller must be a pointer to struct")
-	}
-
-	var perActionMW = map[string][]HandlerFunc{}
-	if m := cv.MethodByName("Uses"); m.IsValid() {
-		if uses, ok := m.Interface().(func() map[string][]HandlerFunc); ok {
-			perActionMW = uses()
-		}
-	}
-
-	resName := strings.ToLower(ct.Elem().Name())
-	basePath = strings.TrimRight(basePath, "/") + "/" + resName
-
-	r.Group(basePath, func() {
-		for action, methods := range RESTFulActions {
-			m := cv.MethodByName(action)
-			if !m.IsValid() {
-				continue
-			}
-			handler, ok := m.Interface().(func(*Context))
-			if !ok {
-				continue
-			}
-			routeName := resName + "_" + strings.ToLower(action)
-
-			var route *Route
-			switch action {
-			case IndexAction, StoreAction:
-				route = r.AddNamed(routeName, "/", handler, methods...)
-			case CreateAction:
-				route = r.AddNamed(routeName, "/"+strings.ToLower(action)+"/", handler, methods...)
-			case EditAction:
-				route = r.AddNamed(routeName, "{id}/"+strings.ToLower(action)+"/", handler, methods...)
-			default: // Show, Update, Delete
-				route = r.AddNamed(routeName, "{id}/", handler, methods...)
-			}
+	r.group().Resource(basePath, controller, middles...)
+}
 
-			if mws, ok := perActionMW[action]; ok {
-				route.Use(mws...)
-			}
-		}
-	}, middles...)
+// ResourceWith registers RESTful routes for the controller struct,
+// configured by opts, and returns the resource. See RouteGroup.ResourceWith.
+func (r *Router) ResourceWith(basePath string, controller any, opts ResourceOptions) *Resource {
+	return r.group().ResourceWith(basePath, controller, opts)
 }
 
 /*************************************************************
@@ -463,34 +530,25 @@ func (r *Router) Resource(basePath string, controller any, middles ...HandlerFun
 
 // StaticFile registers a single static file under the given path.
 func (r *Router) StaticFile(path, filePath string) *Route {
-	return r.GET(path, func(c *Context) { c.File(filePath) })
+	return r.group().StaticFile(path, filePath)
 }
 
 // StaticDir serves files from rootDir under prefixURL using http.FileServer.
 func (r *Router) StaticDir(prefixURL, rootDir string) *Route {
-	fs := http.StripPrefix(prefixURL, http.FileServer(http.Dir(rootDir)))
-	return r.GET(prefixURL+"/*file", func(c *Context) {
-		fs.ServeHTTP(c.Resp, c.Req)
-	})
+	return r.group().StaticDir(prefixURL, rootDir)
 }
 
 // StaticFS serves files from the given http.FileSystem under prefixURL.
+// The matched prefix (including any group prefix) is stripped the same
+// way as for Mount.
 func (r *Router) StaticFS(prefixURL string, fs http.FileSystem) *Route {
-	handler := http.StripPrefix(prefixURL, http.FileServer(fs))
-	return r.GET(prefixURL+"/*file", func(c *Context) {
-		handler.ServeHTTP(c.Resp, c.Req)
-	})
+	return r.group().StaticFS(prefixURL, fs)
 }
 
 // StaticFiles serves files from rootDir under prefixURL. The exts argument
 // is reserved for future extension filtering and is currently ignored.
 func (r *Router) StaticFiles(prefixURL, rootDir, exts string) *Route {
-	fs := http.FileServer(http.Dir(rootDir))
-	_ = exts // reserved for future extension filtering
-	return r.GET(fmt.Sprintf("%s/*file", prefixURL), func(c *Context) {
-		c.Req.URL.Path = c.Param("file")
-		fs.ServeHTTP(c.Resp, c.Req)
-	})
+	return r.group().StaticFiles(prefixURL, rootDir, exts)
 }
 
 /*************************************************************
@@ -498,23 +556,80 @@ func (r *Router) StaticFiles(prefixURL, rootDir, exts string) *Route {
  *************************************************************/
 
 // GetRoute returns a named route or nil.
-func (r *Router) GetRoute(name string) *Route { return r.namedRoutes[name] }
+func (r *Router) GetRoute(name string) *Route {
+	rt := r.current()
+	if _, names := rt.pendingRoutes(); names[name] != nil {
+		return names[name]
+	}
+	return rt.namedRoutes[name]
+}
 
 // NamedRoutes returns the map of named routes.
-func (r *Router) NamedRoutes() map[string]*Route { return r.namedRoutes }
+func (r *Router) NamedRoutes() map[string]*Route {
+	rt := r.current()
+	_, names := rt.pendingRoutes()
+	if len(names) == 0 {
+		return rt.namedRoutes
+	}
+	merged := maps.Clone(rt.namedRoutes)
+	maps.Copy(merged, names)
+	return merged
+}
+
+// pendingRoutes returns the API version routes added on Freeze, see
+// APIVersions.pending, and their names. They are nil once r is frozen.
+func (r *Router) pendingRoutes() ([]*Route, map[string]*Route) {
+	if r.frozen.Load() {
+		return nil, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.versionSets) == 0 {
+		return nil, nil
+	}
+	var routes []*Route
+	names := make(map[string]*Route)
+	for _, vs := range r.versionSets {
+		routes = append(routes, vs.pending(names)...)
+	}
+	return routes, names
+}
+
+// listedRoutes returns the routes of r in registration order, the API
+// version routes included before Freeze.
+func (r *Router) listedRoutes() []*Route {
+	pending, _ := r.pendingRoutes()
+	if len(pending) == 0 {
+		return r.routeList
+	}
+	return append(slices.Clip(r.routeList), pending...)
+}
 
 // Routes returns all routes as RouteInfo snapshots in registration order.
+// A Mount route is followed by the routes of its sub-router, with the
+// mount prefix prepended to their paths.
 func (r *Router) Routes() []RouteInfo {
-	out := make([]RouteInfo, 0, len(r.routeList))
-	for _, route := range r.routeList {
-		out = append(out, route.Info())
+	if rt := r.current(); rt != r {
+		return rt.Routes()
+	}
+	routes := r.listedRoutes()
+	out := make([]RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		out = append(out, route.info(r.effectiveChain(route)))
+		if sub := route.mounted; sub != nil {
+			prefix := strings.TrimRight(route.MountPrefix(), "/")
+			for _, info := range sub.Routes() {
+				info.Path = prefix + info.Path
+				out = append(out, info)
+			}
+		}
 	}
 	return out
 }
 
 // IterateRoutes calls fn for each registered route in registration order.
 func (r *Router) IterateRoutes(fn func(*Route)) {
-	for _, route := range r.routeList {
+	for _, route := range r.current().routeList {
 		fn(route)
 	}
 }
@@ -524,12 +639,34 @@ func (r *Router) Err() error { return r.err }
 
 // String returns a human-readable snapshot of registered routes.
 func (r *Router) String() string {
+	if rt := r.current(); rt != r {
+		return rt.String()
+	}
 	var b strings.Builder
 	fmt.Fprintf(&b, "Routes Count: %d\n", r.counter)
+	r.writeRoutes(&b, "  ", "")
+	return b.String()
+}
+
+// effectiveChain returns the handlers dispatched for route: its final
+// chain once frozen, otherwise the global chain followed by the route chain.
+func (r *Router) effectiveChain(route *Route) HandlersChain {
+	if route.finalChain != nil || len(r.globalChain) == 0 {
+		return route.effectiveChain()
+	}
+	return append(append(HandlersChain{}, r.globalChain...), route.chain...)
+}
+
+// writeRoutes writes one line per route with its effective middlewares;
+// mounted sub-router routes follow their mount route, indented and with
+// the mount prefix prepended.
+func (r *Router) writeRoutes(b *strings.Builder, indent, prefix string) {
 	for _, route := range r.routeList {
-		fmt.Fprintf(&b, "  %s\n", route)
+		fmt.Fprintf(b, "%s%s\n", indent, route.format(prefix+route.host+route.path, r.effectiveChain(route)))
+		if sub := route.mounted; sub != nil {
+			sub.writeRoutes(b, indent+"  ", prefix+strings.TrimRight(route.MountPrefix(), "/"))
+		}
 	}
-	return b.String()
 }
 
 // BuildURL builds a request URL for the named route. buildArgs are forwarded
@@ -550,37 +687,63 @@ func (r *Router) BuildRequestURL(name string, buildArgs ...any) *url.URL {
 // Match looks up route + params for an offline test or debugging.
 // The hot ServeHTTP path does NOT call this — it uses an internal
 // signature that writes params directly into Context with zero allocation.
+//
+// Route conditions are not evaluated: of several routes sharing the
+// method and path, the one without conditions is returned, else the one
+// tried last.
 func (r *Router) Match(method, path string) (*Route, []Param, bool) {
+	if rt := r.current(); rt != r {
+		return rt.Match(method, path)
+	}
 	if !r.frozen.Load() {
 		r.Freeze()
 	}
-	idx := methodIndex(strings.ToUpper(method))
+	idx := r.methods.index(strings.ToUpper(method))
 	if idx < 0 {
 		return nil, nil, false
 	}
 	path = r.formatPath(path)
 
-	if m := r.staticRoutes[idx]; m != nil {
-		if route, ok := m[path]; ok {
+	var ps Params
+	if route := r.routeTable.match(idx, path, &ps); route != nil {
+		route = route.primary()
+		if ps.n == 0 {
 			return route, nil, true
 		}
-	}
-	if tree := r.dynamicTrees[idx]; tree != nil {
-		var ps Params
-		if route, ok := tree.lookup(path, &ps); ok {
-			return route, ps.Snapshot(), true
-		}
+		return route, ps.Snapshot(), true
 	}
 	return nil, nil, false
 }
 
 // formatPath applies the router's path policy.
 func (r *Router) formatPath(path string) string {
+	return formatPath(path, r.strictLastSlash)
+}
+
+// routePath normalizes a registered path for the route tables. The
+// trailing slash is kept with StrictLastSlash.
+func (r *Router) routePath(path string) string {
+	p := normalizePath(path)
+	if r.strictLastSlash && p != "/" && strings.HasSuffix(path, "/") {
+		p += "/"
+	}
+	return p
+}
+
+// requestPath formats the request path for matching. The trailing slash
+// is kept for RedirectTrailingSlash.
+func (r *Router) requestPath(path string) string {
+	return formatPath(path, r.strictLastSlash || r.redirectTrailingSlash)
+}
+
+// formatPath trims spaces, adds the leading slash, collapses leading
+// slashes and, unless keepSlash, trims trailing slashes.
+func formatPath(path string, keepSlash bool) string {
 	if path == "" || path == "/" {
 		return "/"
 	}
 	path = strings.TrimSpace(path)
-	if !r.strictLastSlash && len(path) > 1 && path[len(path)-1] == '/' {
+	if !keepSlash && len(path) > 1 && path[len(path)-1] == '/' {
 		path = strings.TrimRight(path, "/")
 	}
 	if path == "" || path == "/" {
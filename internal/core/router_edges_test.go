@@ -65,13 +65,51 @@ func TestFormatPath_AddsLeadingSlash(t *testing.T) {
 // Use / Group panics + nested
 // =========================================================================
 
-func TestRouter_Use_AfterRouteRegistration_Panics(t *testing.T) {
+func TestRouter_Use_AfterRouteRegistration_Scoping(t *testing.T) {
+	var calls []string
+	mw := func(name string) HandlerFunc {
+		return func(c *Context) { calls = append(calls, name); c.Next() }
+	}
+
 	r := New()
-	r.GET("/x", func(c *Context) {})
-	defer func() { assert.NotNil(t, recover()) }()
-	r.Use(func(c *Context) {})
+	r.GET("/early", textHandler("early"), mw("route"))
+	r.UseFromNow(mw("scoped"))
+	r.Group("/g", func() {
+		r.GET("/late", textHandler("late"), mw("route"))
+	}, mw("group"))
+	r.Use(mw("global"))
+
+	serve(r, GET, "/early")
+	assert.Eq(t, []string{"global", "route"}, calls)
+
+	calls = nil
+	serve(r, GET, "/g/late")
+	assert.Eq(t, []string{"global", "scoped", "group", "route"}, calls)
+
+	assert.Panics(t, func() { r.UseFromNow(mw("x")) })
 }
 
+func TestRouter_RouteDumpShowsEffectiveChain(t *testing.T) {
+	r := New()
+	route := r.GET("/x", textHandler("x"), testMiddleware)
+	r.Use(testGlobalMiddleware)
+
+	infos := r.Routes()
+	assert.Eq(t, 2, infos[0].HandlerNum)
+	assert.Len(t, infos[0].Middlewares, 2)
+	assert.StrContains(t, infos[0].Middlewares[0], "testGlobalMiddleware")
+	assert.StrContains(t, infos[0].Middlewares[1], "testMiddleware")
+	assert.StrContains(t, r.String(), "(2 middleware: ")
+
+	// Dumps read the same chain after Freeze.
+	r.Freeze()
+	assert.Eq(t, infos, r.Routes())
+	assert.StrContains(t, route.String(), "(2 middleware: ")
+}
+
+func testMiddleware(c *Context)       { c.Next() }
+func testGlobalMiddleware(c *Context) { c.Next() }
+
 func TestRouter_Use_AfterFreeze_Panics(t *testing.T) {
 	r := New()
 	r.GET("/x", func(c *Context) {})
@@ -177,6 +215,21 @@ func TestStaticDir_ServesFile(t *testing.T) {
 	assert.Eq(t, "alpha", w.Body.String())
 }
 
+func TestStaticDir_InGroup_StripsGroupPrefix(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoErr(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha"), 0644))
+
+	r := New()
+	r.Group("/ui", func() {
+		r.StaticDir("/static", dir)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/ui/static/a.txt", nil))
+	assert.Eq(t, 200, w.Code)
+	assert.Eq(t, "alpha", w.Body.String())
+}
+
 func TestStaticFS_ServesFile(t *testing.T) {
 	dir := t.TempDir()
 	assert.NoErr(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("beta"), 0644))
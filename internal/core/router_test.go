@@ -140,12 +140,12 @@ func TestRouter_Use_AddsToGlobalChain(t *testing.T) {
 	assert.Eq(t, 1, len(r.globalChain))
 }
 
-func TestRouter_UseAfterRouteRegistration_Panics(t *testing.T) {
+func TestRouter_UseAfterRouteRegistration_AppliesToAll(t *testing.T) {
 	r := New()
-	r.GET("/x", func(c *Context) {})
-	assert.Panics(t, func() {
-		r.Use(func(c *Context) {})
-	})
+	route := r.GET("/x", func(c *Context) {})
+	r.Use(func(c *Context) {})
+	r.Freeze()
+	assert.Eq(t, 2, len(route.finalChain))
 }
 
 func TestRouter_NotFound(t *testing.T) {
@@ -319,6 +319,44 @@ func TestMatch_Dynamic(t *testing.T) {
 	assert.Eq(t, "42", params[0].Value)
 }
 
+func TestMatch_MixedSegment(t *testing.T) {
+	r := New(HandleOptions)
+	r.AddNamed("file", "/files/{name}.{ext}", textHandler("file"), GET)
+	r.PUT("/files/{n}.{e}", textHandler("put"))
+	r.GET("/v{major:\\d+}.{minor:\\d+}/status", textHandler("status"))
+	r.Freeze()
+
+	_, params, ok := r.Match(GET, "/files/photo.jpeg")
+	assert.True(t, ok)
+	assert.Eq(t, []Param{{"name", "photo"}, {"ext", "jpeg"}}, params)
+	_, params, ok = r.Match(GET, "/v2.10/status")
+	assert.True(t, ok)
+	assert.Eq(t, []Param{{"major", "2"}, {"minor", "10"}}, params)
+	_, _, ok = r.Match(GET, "/vx.1/status")
+	assert.False(t, ok)
+
+	assert.Eq(t, "GET, HEAD, OPTIONS, PUT", serve(r, OPTIONS, "/files/a.b").Header().Get("Allow"))
+	assert.Eq(t, "/files/x.y", r.BuildURL("file", M{"{name}": "x", "{ext}": "y"}).Path)
+}
+
+// Only the brace form splits a segment: ":name" and a "{name}" ending its
+// segment keep every byte up to the next '/' in the name.
+func TestMatch_ParamNamesWithPunctuation(t *testing.T) {
+	r := New()
+	r.GET("/u/{user-id}", func(c *Context) { c.Text(200, "u "+c.Param("user-id")) })
+	r.GET("/v/:post.id", func(c *Context) { c.Text(200, "v "+c.Param("post.id")) })
+	r.GET("/w/{name}.{ext}", func(c *Context) { c.Text(200, c.Param("name")+" "+c.Param("ext")) })
+
+	w := serve(r, GET, "/u/42")
+	assert.Eq(t, 200, w.Code)
+	assert.Eq(t, "u 42", w.Body.String())
+	assert.Eq(t, "u 42-id", serve(r, GET, "/u/42-id").Body.String())
+	w = serve(r, GET, "/v/7")
+	assert.Eq(t, 200, w.Code)
+	assert.Eq(t, "v 7", w.Body.String())
+	assert.Eq(t, "a.tar gz", serve(r, GET, "/w/a.tar.gz").Body.String())
+}
+
 func TestMatch_Miss(t *testing.T) {
 	r := New()
 	r.Freeze()
@@ -0,0 +1,81 @@
+package core
+
+import "fmt"
+
+// current returns the router whose route set serves requests: the route
+// set last installed by Swap, or r itself.
+func (r *Router) current() *Router {
+	if rs := r.active.Load(); rs != nil {
+		return rs
+	}
+	return r
+}
+
+// NewRouteSet returns an empty Router for building a replacement route set
+// off to the side. It is created with the options passed to New and starts
+// with r's global middlewares and NotFound / NotAllowed / GlobalOptions
+// handlers; more can be added before registering routes on it.
+//
+//	rs := r.NewRouteSet()
+//	rs.GET("/feature", featureHandler)
+//	r.Swap(rs)
+func (r *Router) NewRouteSet() *Router {
+	rs := New(r.opts...)
+	rs.Name = r.Name
+	rs.methods = r.methods
+	rs.globalChain = append(HandlersChain(nil), r.globalChain...)
+	rs.noRoute = r.noRoute
+	rs.noAllowed = r.noAllowed
+	rs.optionsChain = r.optionsChain
+	return rs
+}
+
+// Swap atomically replaces the routing table of r with the routes of next
+// and returns the previously active route set (r itself on the first swap).
+// Swap(r) switches back to r's own routes.
+//
+// Requests already dispatched finish on the old table; requests that start
+// after Swap returns see the new one. Pooled Contexts keep r as their
+// Router, and options plus the OnError / OnPanic hooks still come from r.
+// Both r and next are frozen.
+func (r *Router) Swap(next *Router) (prev *Router) {
+	if next == nil {
+		panic("rux: Swap route set cannot be nil")
+	}
+	r.Freeze()
+
+	var old *Router
+	if next == r {
+		old = r.active.Swap(nil)
+	} else {
+		next.Freeze()
+		old = r.active.Swap(next)
+	}
+	if old == nil {
+		return r
+	}
+	return old
+}
+
+// Reload builds a route set with fn and installs it via Swap. A panic
+// raised while building (e.g. a duplicate route loaded from config) is
+// returned as an error and leaves the active routes untouched.
+func (r *Router) Reload(fn func(rs *Router)) (prev *Router, err error) {
+	rs := r.NewRouteSet()
+	if err = buildRouteSet(rs, fn); err != nil {
+		return nil, err
+	}
+	return r.Swap(rs), nil
+}
+
+// buildRouteSet runs fn on rs and freezes it, converting panics to errors.
+func buildRouteSet(rs *Router, fn func(rs *Router)) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("rux: build route set: %v", rec)
+		}
+	}()
+	fn(rs)
+	rs.Freeze()
+	return nil
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+// versionedRouteSet builds a route set whose middleware and handler both
+// report name, so a response mixing two route sets is detectable.
+func versionedRouteSet(r *Router, name string) *Router {
+	rs := r.NewRouteSet()
+	rs.Use(func(c *Context) { c.SetHeader("X-Set", name); c.Next() })
+	rs.GET("/v/{id}", func(c *Context) { c.Text(200, name) })
+	rs.GET("/static", func(c *Context) { c.Text(200, name) })
+	return rs
+}
+
+func TestRouter_Swap_ReplacesRoutes(t *testing.T) {
+	r := New(HandleMethodNotAllowed)
+	r.Use(func(c *Context) { c.SetHeader("X-Global", "1"); c.Next() })
+	r.AddNamed("old", "/old", textHandler("old"), GET)
+
+	rs := r.NewRouteSet()
+	assert.True(t, rs.handleMethodNotAllowed)
+	rs.AddNamed("new", "/new/{id}", textHandler("new"), GET)
+
+	prev := r.Swap(rs)
+	assert.Same(t, r, prev)
+	assert.True(t, rs.Frozen())
+
+	w := serve(r, "GET", "/new/1")
+	assert.Eq(t, "new", w.Body.String())
+	assert.Eq(t, "1", w.Header().Get("X-Global"))
+	assert.Eq(t, 404, serve(r, "GET", "/old").Code)
+	assert.Eq(t, 405, serve(r, "POST", "/new/1").Code)
+
+	// Inspection APIs follow the active route set.
+	assert.Nil(t, r.GetRoute("old"))
+	assert.NotNil(t, r.GetRoute("new"))
+	assert.Eq(t, "/new/5", r.BuildURL("new", M{"{id}": 5}).Path)
+	assert.Eq(t, 1, len(r.Routes()))
+	_, _, ok := r.Match(GET, "/new/2")
+	assert.True(t, ok)
+
+	// Swapping r back restores its own routes.
+	assert.Same(t, rs, r.Swap(r))
+	assert.Eq(t, "old", serve(r, "GET", "/old").Body.String())
+}
+
+func TestRouter_Swap_ContextRouterIsStable(t *testing.T) {
+	r := New()
+	rs := r.NewRouteSet()
+	var got *Router
+	rs.GET("/x", func(c *Context) { got = c.Router() })
+	r.Swap(rs)
+
+	serve(r, "GET", "/x")
+	assert.Same(t, r, got)
+}
+
+func TestRouter_Reload(t *testing.T) {
+	r := New()
+	r.GET("/a", textHandler("a"))
+
+	_, err := r.Reload(func(rs *Router) {
+		rs.GET("/b", textHandler("b"))
+		rs.GET("/b", textHandler("dup"))
+	})
+	assert.Err(t, err)
+	assert.Eq(t, "a", serve(r, "GET", "/a").Body.String())
+
+	prev, err := r.Reload(func(rs *Router) { rs.GET("/b", textHandler("b")) })
+	assert.NoErr(t, err)
+	assert.Same(t, r, prev)
+	assert.Eq(t, "b", serve(r, "GET", "/b").Body.String())
+	assert.Eq(t, 404, serve(r, "GET", "/a").Code)
+
+	assert.Panics(t, func() { r.Swap(nil) })
+}
+
+func TestRouter_Swap_ConcurrentServeHTTP(t *testing.T) {
+	r := New()
+	sets := []*Router{
+		versionedRouteSet(r, "A"),
+		versionedRouteSet(r, "B"),
+		versionedRouteSet(r, "C"),
+	}
+	r.Swap(sets[0])
+
+	var (
+		stop   atomic.Bool
+		failed atomic.Value
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			paths := []string{"/v/1", "/static"}
+			for n := 0; !stop.Load(); n++ {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest("GET", paths[(i+n)%2], nil))
+				if w.Code != 200 || w.Header().Get("X-Set") != w.Body.String() {
+					failed.Store(errors.New("torn read: code=" + w.Result().Status +
+						" header=" + w.Header().Get("X-Set") + " body=" + w.Body.String()))
+					return
+				}
+			}
+		}(i)
+	}
+
+	for n := 0; n < 2000; n++ {
+		r.Swap(sets[n%len(sets)])
+	}
+	stop.Store(true)
+	wg.Wait()
+
+	if err, ok := failed.Load().(error); ok {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,172 @@
+package core
+
+// routeTable is the per-method route storage: one exact-path map for static
+// routes and one radix tree for dynamic routes, per HTTP method.
+//
+// The Router embeds the host-less table; each host pattern registered via
+// Router.Host gets its own table (see host.go).
+type routeTable struct {
+	// Per-method static routes. Indexed by methodSet.index().
+	// nil for methods that have no static routes registered yet.
+	staticRoutes [maxMethods]map[string]*Route
+
+	// Per-method dynamic radix trees. nil if no dynamic routes for that method.
+	dynamicTrees [maxMethods]*radixTree
+
+	// allow holds the Allow headers of the static paths for HandleOptions,
+	// built on Freeze.
+	allow map[string]allowEntry
+}
+
+// register stores route in the right per-method bucket, indexed by ms.
+func (t *routeTable) register(route *Route, ms *methodSet) {
+	if isStaticPath(route.path) {
+		for _, m := range route.methods {
+			idx := ms.index(m)
+			if idx < 0 {
+				panic("rux: unknown method " + m)
+			}
+			if t.staticRoutes[idx] == nil {
+				t.staticRoutes[idx] = make(map[string]*Route, 4)
+			}
+			slot, ok := addVariant(t.staticRoutes[idx][route.path], route)
+			if !ok {
+				panic("rux: duplicate static route: " + m + " " + route.path)
+			}
+			t.staticRoutes[idx][route.path] = slot
+		}
+		return
+	}
+
+	for _, m := range route.methods {
+		idx := ms.index(m)
+		if idx < 0 {
+			panic("rux: unknown method " + m)
+		}
+		if t.dynamicTrees[idx] == nil {
+			t.dynamicTrees[idx] = newRadixTree()
+		}
+		t.dynamicTrees[idx].insert(route.path, route)
+	}
+}
+
+// clone returns a deep copy of t, for registration to be rolled back.
+func (t *routeTable) clone() routeTable {
+	var c routeTable
+	for i, m := range t.staticRoutes {
+		if m == nil {
+			continue
+		}
+		c.staticRoutes[i] = make(map[string]*Route, len(m))
+		for p, route := range m {
+			c.staticRoutes[i][p] = cloneSlot(route)
+		}
+	}
+	for i, tree := range t.dynamicTrees {
+		if tree != nil {
+			c.dynamicTrees[i] = tree.clone()
+		}
+	}
+	return c
+}
+
+// match looks up path for the method at idx. Matched params are appended
+// to ps. Returns nil on miss or for an unknown method (idx < 0).
+func (t *routeTable) match(idx int, path string, ps *Params) *Route {
+	if idx < 0 {
+		return nil
+	}
+	if m := t.staticRoutes[idx]; m != nil {
+		if route, ok := m[path]; ok {
+			return route
+		}
+	}
+	if tree := t.dynamicTrees[idx]; tree != nil {
+		if route, ok := tree.lookup(path, ps); ok {
+			return route
+		}
+	}
+	return nil
+}
+
+// maxParams returns the largest param count over all method trees.
+func (t *routeTable) maxParams() int {
+	var n uint8
+	for _, tree := range t.dynamicTrees {
+		if tree != nil && tree.maxParams > n {
+			n = tree.maxParams
+		}
+	}
+	return int(n)
+}
+
+// mirrorGetToHead copies every GET route to HEAD unless an explicit HEAD
+// route already exists at that path. Required by P-9.
+func (t *routeTable) mirrorGetToHead() {
+	getIdx := methodIndex(GET)
+	headIdx := methodIndex(HEAD)
+
+	// Static.
+	if getStatic := t.staticRoutes[getIdx]; getStatic != nil {
+		if t.staticRoutes[headIdx] == nil {
+			t.staticRoutes[headIdx] = make(map[string]*Route, len(getStatic))
+		}
+		head := t.staticRoutes[headIdx]
+		for path, route := range getStatic {
+			if _, exists := head[path]; !exists {
+				head[path] = route
+			}
+		}
+	}
+
+	// Dynamic.
+	if get := t.dynamicTrees[getIdx]; get != nil {
+		if t.dynamicTrees[headIdx] == nil {
+			t.dynamicTrees[headIdx] = newRadixTree()
+		}
+		head := t.dynamicTrees[headIdx]
+		get.walk(func(path string, leaf *node) {
+			if !head.hasExact(path) {
+				head.insert(path, leaf.route)
+			}
+		})
+	}
+}
+
+// allowedMethods appends to allowed the HTTP methods of ms (other than
+// method) that would match path in this table, skipping ones already
+// listed.
+func (t *routeTable) allowedMethods(ms *methodSet, allowed []string, method, path string) []string {
+	for _, m := range ms.all {
+		if m == method || containsString(allowed, m) {
+			continue
+		}
+		idx := ms.index(m)
+		if idx < 0 {
+			continue
+		}
+		if t.staticRoutes[idx] != nil {
+			if _, ok := t.staticRoutes[idx][path]; ok {
+				allowed = append(allowed, m)
+				continue
+			}
+		}
+		if tree := t.dynamicTrees[idx]; tree != nil {
+			var ps Params
+			if _, ok := tree.lookup(path, &ps); ok {
+				allowed = append(allowed, m)
+			}
+		}
+	}
+	return allowed
+}
+
+// containsString reports whether ss contains s.
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -24,16 +26,32 @@ const (
 //   - For nType == nodeWildcard: prefix == "*" + paramName, paramName != "".
 //   - len(indices) == len(children).
 //   - At most one paramChild and one wildcardChild per node.
+//   - A param node has no param or wildcard children: two params in a
+//     row ({a}{b}) are ambiguous.
+//   - regexChildren holds constrained params ({id:\d+}), one per distinct
+//     pattern; each has a non-nil regex.
 type node struct {
 	prefix    string
 	nType     nodeType
 	paramName string
 
+	// pattern is the constraint source of a constrained param node, and
+	// regex its anchored compiled form. Both empty/nil when unconstrained.
+	pattern string
+	regex   *regexp.Regexp
+
+	// inSegment is set on a param node with a static child that continues
+	// the same path segment, as the "." in {name}.{ext}. Only such nodes
+	// try values shorter than the whole segment.
+	inSegment bool
+
 	// Static children, kept sorted by priority desc.
 	indices  []byte
 	children []*node
 
-	// Dynamic children (one each).
+	// Dynamic children. Constrained params are tried in registration
+	// order, before the single unconstrained paramChild.
+	regexChildren []*node
 	paramChild    *node
 	wildcardChild *node
 
@@ -112,10 +130,11 @@ func (t *radixTree) insert(path string, route *Route) {
 
 		// STEP C: Path exhausted at this node.
 		if len(remaining) == 0 {
-			if n.route != nil {
+			slot, ok := addVariant(n.route, route)
+			if !ok {
 				panic("rux: duplicate route registration: " + path)
 			}
-			n.route = route
+			n.route = slot
 			n.chain = route.chain
 			t.bumpAlongPath(path)
 			return
@@ -125,30 +144,33 @@ func (t *radixTree) insert(path string, route *Route) {
 		c := remaining[0]
 
 		switch c {
-		case ':':
-			// Param: consume the ":name" segment, descend into paramChild.
-			end := strings.IndexByte(remaining, '/')
+		case '{':
+			// Brace param: consume the "{name}" or "{name:regex}" and
+			// descend into the param or regex child. Only the brace form
+			// may be followed by a literal within the segment.
+			end := closingBrace(remaining, 0)
 			if end == -1 {
-				end = len(remaining)
+				panic("rux: unbalanced brace in path " + path)
 			}
-			name := remaining[1:end]
-			if name == "" {
-				panic("rux: empty param name in path " + path)
+			name, pattern := splitParamDef(remaining[1:end])
+			end++
+			checkParamFollower(remaining, end, path)
+			if pattern == "" {
+				n = t.paramChild(n, name, path)
+			} else {
+				n = t.regexChild(n, name, pattern, path)
 			}
-			if n.paramChild == nil {
-				n.paramChild = &node{
-					// Empty prefix — see invariants above.
-					prefix:    "",
-					nType:     nodeParam,
-					paramName: name,
-				}
-			} else if n.paramChild.paramName != name {
-				panic(fmt.Sprintf("rux: conflicting param names %q vs %q at %s",
-					n.paramChild.paramName, name, path))
-			}
-			t.bumpMaxParams(t.countParams(path))
-			n = n.paramChild
-			// Manually advance past the param segment — the empty prefix
+			t.bumpMaxParams(countParams(path))
+			remaining = remaining[end:]
+			continue
+
+		case ':':
+			// Param: consume the ":name", descend into paramChild. The
+			// name runs to the end of the segment.
+			end := paramNameEnd(remaining, 1)
+			n = t.paramChild(n, remaining[1:end], path)
+			t.bumpMaxParams(countParams(path))
+			// Manually advance past the param name — the empty prefix
 			// means the next iteration's prefix-match consumes nothing.
 			remaining = remaining[end:]
 			continue
@@ -160,16 +182,22 @@ func (t *radixTree) insert(path string, route *Route) {
 				panic("rux: empty wildcard name in path " + path)
 			}
 			if n.wildcardChild != nil {
-				panic("rux: conflicting wildcard at " + path)
+				slot, ok := addVariant(n.wildcardChild.route, route)
+				if n.wildcardChild.paramName != name || !ok {
+					panic("rux: conflicting wildcard at " + path)
+				}
+				n.wildcardChild.route = slot
+				return
 			}
+			slot, _ := addVariant(nil, route)
 			n.wildcardChild = &node{
 				prefix:    "",
 				nType:     nodeWildcard,
 				paramName: name,
-				route:     route,
+				route:     slot,
 				chain:     route.chain,
 			}
-			t.bumpMaxParams(t.countParams(path))
+			t.bumpMaxParams(countParams(path))
 			t.bumpAlongPath(path)
 			return
 
@@ -182,6 +210,9 @@ func (t *radixTree) insert(path string, route *Route) {
 				// will consume exactly child.prefix's worth.
 				continue
 			}
+			if n.nType == nodeParam && c != '/' {
+				n.inSegment = true
+			}
 			// Create a new static child. cut = how many bytes to consume.
 			cut := indexOfDynamicMarker(remaining)
 			if cut < 0 {
@@ -200,6 +231,58 @@ func (t *radixTree) insert(path string, route *Route) {
 	}
 }
 
+// paramChild returns n's unconstrained param child, creating it on first
+// use. A different name at the same position is ambiguous and panics.
+func (t *radixTree) paramChild(n *node, name, path string) *node {
+	if name == "" {
+		panic("rux: empty param name in path " + path)
+	}
+	if n.paramChild == nil {
+		n.paramChild = &node{
+			// Empty prefix — see invariants above.
+			prefix:    "",
+			nType:     nodeParam,
+			paramName: name,
+		}
+	} else if n.paramChild.paramName != name {
+		panic(fmt.Sprintf("rux: conflicting param names %q vs %q at %s",
+			n.paramChild.paramName, name, path))
+	}
+	return n.paramChild
+}
+
+// regexChild returns n's constrained param child for pattern, creating it
+// on first use. Two different names under the same pattern are ambiguous
+// and panic, like conflicting plain param names.
+func (t *radixTree) regexChild(n *node, name, pattern, path string) *node {
+	if name == "" {
+		panic("rux: empty param name in path " + path)
+	}
+	for _, child := range n.regexChildren {
+		if child.pattern != pattern {
+			continue
+		}
+		if child.paramName != name {
+			panic(fmt.Sprintf("rux: conflicting param names %q vs %q at %s",
+				child.paramName, name, path))
+		}
+		return child
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		panic(fmt.Sprintf("rux: invalid regex for param %q in path %s: %v", name, path, err))
+	}
+	child := &node{
+		prefix:    "",
+		nType:     nodeParam,
+		paramName: name,
+		pattern:   pattern,
+		regex:     re,
+	}
+	n.regexChildren = append(n.regexChildren, child)
+	return child
+}
+
 // splitNode splits node n at byte index splitIdx into a parent
 // (n.prefix[:splitIdx]) with one child holding the remainder
 // (n.prefix[splitIdx:]) along with all of n's previous children,
@@ -214,8 +297,12 @@ func (t *radixTree) splitNode(n *node, splitIdx int) {
 		prefix:        n.prefix[splitIdx:],
 		nType:         n.nType,
 		paramName:     n.paramName,
+		pattern:       n.pattern,
+		regex:         n.regex,
+		inSegment:     n.inSegment,
 		indices:       n.indices,
 		children:      n.children,
+		regexChildren: n.regexChildren,
 		paramChild:    n.paramChild,
 		wildcardChild: n.wildcardChild,
 		chain:         n.chain,
@@ -227,8 +314,12 @@ func (t *radixTree) splitNode(n *node, splitIdx int) {
 	n.prefix = n.prefix[:splitIdx]
 	n.nType = nodeStatic
 	n.paramName = ""
+	n.pattern = ""
+	n.regex = nil
+	n.inSegment = false
 	n.indices = nil
 	n.children = nil
+	n.regexChildren = nil
 	n.paramChild = nil
 	n.wildcardChild = nil
 	n.chain = nil
@@ -237,59 +328,125 @@ func (t *radixTree) splitNode(n *node, splitIdx int) {
 	n.addStaticChild(child)
 }
 
-// indexOfDynamicMarker returns the index of the first ':' or '*' in s, or -1.
+// paramNameEnd returns the index just past the ":name" param name
+// starting at s[i]: the name runs to the end of the segment, so
+// "/:user-id" and "/:post.id" keep their whole names. Params followed by
+// a literal use the brace form, "{name}.{ext}".
+func paramNameEnd(s string, i int) int {
+	if end := strings.IndexByte(s[i:], '/'); end >= 0 {
+		return i + end
+	}
+	return len(s)
+}
+
+// checkParamFollower panics if the param ending at remaining[end] is
+// directly followed by another param: there is no literal telling where
+// one value ends and the next starts.
+func checkParamFollower(remaining string, end int, path string) {
+	if end < len(remaining) && strings.IndexByte(":*{", remaining[end]) >= 0 {
+		panic("rux: adjacent params without a literal between them in path " + path)
+	}
+}
+
+// indexOfDynamicMarker returns the index of the first ':', '*' or '{' in s,
+// or -1.
 func indexOfDynamicMarker(s string) int {
 	for i := 0; i < len(s); i++ {
-		if s[i] == ':' || s[i] == '*' {
+		if s[i] == ':' || s[i] == '*' || s[i] == '{' {
 			return i
 		}
 	}
 	return -1
 }
 
-// countParams returns the number of ':' and '*' segments in path.
-func (t *radixTree) countParams(path string) uint8 {
-	var n uint8
+// countParams returns the number of ':', '*' and '{...}' segments in path.
+// Bytes inside a constraint regex are skipped.
+func countParams(path string) int {
+	var n int
 	for i := 0; i < len(path); i++ {
-		if path[i] == ':' || path[i] == '*' {
+		switch path[i] {
+		case ':', '*':
+			n++
+		case '{':
 			n++
+			if end := closingBrace(path, i); end > 0 {
+				i = end
+			}
 		}
 	}
 	return n
 }
 
-// bumpMaxParams updates the tree's max param count.
-func (t *radixTree) bumpMaxParams(n uint8) {
-	if n > t.maxParams {
-		t.maxParams = n
+// bumpMaxParams updates the tree's max param count. The router checks
+// the count against its ParamsLimit on registration.
+func (t *radixTree) bumpMaxParams(n int) {
+	if n > maxParamsLimit {
+		n = maxParamsLimit
 	}
-	if t.maxParams > MaxParams {
-		panic(fmt.Sprintf("rux: route exceeds MaxParams=%d", MaxParams))
+	if uint8(n) > t.maxParams {
+		t.maxParams = uint8(n)
 	}
 }
 
 // lookup searches for the route matching path. On success it appends matched
 // path params to ps and returns the route.
 //
-// Priority: static > param > wildcard. (P-2)
+// Priority: static > constrained param > param > wildcard. (P-2)
 //
 // path must already be normalized.
 func (t *radixTree) lookup(path string, ps *Params) (*Route, bool) {
-	return walkNode(t.root, path, ps)
+	return walkNode(t.root, path, ps, nil)
+}
+
+// walkTrace is the optional hook of walkNode and walkParam, reporting the
+// candidates that fail to match to fail, with the route pattern tried.
+// Lookups pass nil.
+type walkTrace struct {
+	// pattern is the route pattern matched above the node walked.
+	pattern string
+	fail    func(pattern, result, detail string)
+}
+
+// failed reports a failed candidate, pattern suffix continuing tr.pattern.
+func (tr *walkTrace) failed(suffix, result, detail string) {
+	tr.fail(tr.pattern+suffix, result, detail)
+}
+
+// below returns the trace of the child of a node with prefix, reached
+// through suffix, or nil without a trace.
+func (tr *walkTrace) below(prefix, suffix string) *walkTrace {
+	if tr == nil {
+		return nil
+	}
+	return &walkTrace{pattern: tr.pattern + prefix + suffix, fail: tr.fail}
+}
+
+// belowParam is below for the param node child.
+func (tr *walkTrace) belowParam(prefix string, child *node) *walkTrace {
+	if tr == nil {
+		return nil
+	}
+	if child.pattern != "" {
+		return tr.below(prefix, "{"+child.paramName+":"+child.pattern+"}")
+	}
+	return tr.below(prefix, ":"+child.paramName)
 }
 
 // walkNode is the recursive lookup. It returns (route, true) on hit;
 // (nil, false) on miss. Backtracking is implicit via the call stack:
 // when a static branch fails, the caller tries param/wildcard fallbacks
-// at the same level.
+// at the same level. tr, if not nil, records the failed candidates.
 //
 // Note: param/wildcard child nodes have prefix == "" (see insert invariants).
 // This means strings.HasPrefix(path, "") is trivially true, and we descend
 // into their children using the same uniform walkNode call — no special
 // "after-param" helper needed.
-func walkNode(n *node, path string, ps *Params) (*Route, bool) {
+func walkNode(n *node, path string, ps *Params, tr *walkTrace) (*Route, bool) {
 	// Match the node's prefix against the start of path.
 	if !strings.HasPrefix(path, n.prefix) {
+		if tr != nil {
+			tr.failed(n.prefix, "prefix mismatch", fmt.Sprintf("path continues with %q", path))
+		}
 		return nil, false
 	}
 	rest := path[len(n.prefix):]
@@ -299,6 +456,9 @@ func walkNode(n *node, path string, ps *Params) (*Route, bool) {
 		if n.route != nil {
 			return n.route, true
 		}
+		if tr != nil {
+			tr.failed(n.prefix, "incomplete", "the path ends here, no route does")
+		}
 		return nil, false
 	}
 
@@ -306,34 +466,33 @@ func walkNode(n *node, path string, ps *Params) (*Route, bool) {
 	snap := ps.n
 
 	// 1. Try static children first (priority order: P-2).
-	if i := n.staticChildIndex(rest[0]); i >= 0 {
-		if r, ok := walkNode(n.children[i], rest, ps); ok {
+	i := n.staticChildIndex(rest[0])
+	if i >= 0 {
+		if r, ok := walkNode(n.children[i], rest, ps, tr.below(n.prefix, "")); ok {
 			return r, true
 		}
 		ps.n = snap
 	}
 
-	// 2. Try param child. Param matches up to the next '/' (or end).
-	if n.paramChild != nil {
+	// 2. Try param children. A param matches up to the next '/' (or end);
+	// constrained ones first, in registration order, then the plain one.
+	if n.paramChild != nil || len(n.regexChildren) > 0 {
 		end := strings.IndexByte(rest, '/')
 		if end == -1 {
 			end = len(rest)
 		}
-		ps.append(n.paramChild.paramName, rest[:end])
-		if end == len(rest) {
-			// Param consumed the rest of the path.
-			if n.paramChild.route != nil {
-				return n.paramChild.route, true
+		for _, child := range n.regexChildren {
+			if r, ok := walkParam(child, rest, end, ps, tr.belowParam(n.prefix, child)); ok {
+				return r, true
 			}
-		} else {
-			// More path remains — descend into paramChild. Empty prefix
-			// means walkNode's prefix-match passes trivially, then it
-			// dispatches on rest[end]'s first byte (typically '/').
-			if r, ok := walkNode(n.paramChild, rest[end:], ps); ok {
+			ps.n = snap
+		}
+		if n.paramChild != nil {
+			if r, ok := walkParam(n.paramChild, rest, end, ps, tr.belowParam(n.prefix, n.paramChild)); ok {
 				return r, true
 			}
+			ps.n = snap
 		}
-		ps.n = snap
 	}
 
 	// 3. Try wildcard child — last resort, matches everything remaining.
@@ -345,9 +504,153 @@ func walkNode(n *node, path string, ps *Params) (*Route, bool) {
 		ps.n = snap
 	}
 
+	if tr != nil && i < 0 && n.paramChild == nil && len(n.regexChildren) == 0 {
+		tr.failed(n.prefix, "incomplete", fmt.Sprintf("no route continues with %q", rest))
+	}
 	return nil, false
 }
 
+// walkParam binds a value to the param node child and continues the
+// lookup below it. The value is rest[:end], the rest of the segment,
+// unless a literal follows the param in the segment ({name}.{ext}): then
+// the longest non-empty value after which the literal matches wins, and
+// the whole segment is tried last. For "a.tar.gz", name is "a.tar".
+//
+// tr, if not nil, is the trace below child. It records the whole segment
+// value only, not the shorter ones tried before.
+func walkParam(child *node, rest string, end int, ps *Params, tr *walkTrace) (*Route, bool) {
+	if child.inSegment {
+		snap := ps.n
+		for k := end - 1; k > 0; k-- {
+			if child.staticChildIndex(rest[k]) < 0 || !child.matchValue(rest[:k]) {
+				continue
+			}
+			ps.append(child.paramName, rest[:k])
+			if r, ok := walkNode(child, rest[k:], ps, nil); ok {
+				return r, true
+			}
+			ps.n = snap
+		}
+	}
+	if !child.matchValue(rest[:end]) {
+		if tr != nil {
+			tr.failed("", "constraint failed", fmt.Sprintf("%q does not match %s", rest[:end], child.pattern))
+		}
+		return nil, false
+	}
+	ps.append(child.paramName, rest[:end])
+	if end == len(rest) {
+		// Param consumed the rest of the path.
+		if child.route == nil && tr != nil {
+			tr.failed("", "incomplete", "the path ends here, no route does")
+		}
+		return child.route, child.route != nil
+	}
+	// More path remains — descend into child. Empty prefix means
+	// walkNode's prefix-match passes trivially, then it dispatches on
+	// rest[end]'s first byte (typically '/').
+	return walkNode(child, rest[end:], ps, tr)
+}
+
+// matchValue reports whether v satisfies the constraint of param node n.
+func (n *node) matchValue(v string) bool {
+	return n.regex == nil || n.regex.MatchString(v)
+}
+
+// findFold returns the registered spelling of path, matched ASCII
+// case-insensitively with the lookup priorities. Param values keep their
+// request case. Used by RedirectFixedPath.
+func (t *radixTree) findFold(path string) (string, bool) {
+	return walkFold(t.root, path, make([]byte, 0, len(path)))
+}
+
+// walkFold is the case-insensitive walkNode. out holds the registered
+// spelling of the path consumed so far.
+func walkFold(n *node, path string, out []byte) (string, bool) {
+	if len(path) < len(n.prefix) || !strings.EqualFold(path[:len(n.prefix)], n.prefix) {
+		return "", false
+	}
+	out = append(out, n.prefix...)
+	rest := path[len(n.prefix):]
+	if len(rest) == 0 {
+		return string(out), n.route != nil
+	}
+
+	lower, upper := toLowerASCII(rest[0]), toUpperASCII(rest[0])
+	for _, b := range [2]byte{lower, upper} {
+		if i := n.staticChildIndex(b); i >= 0 {
+			if s, ok := walkFold(n.children[i], rest, out); ok {
+				return s, true
+			}
+		}
+		if lower == upper {
+			break
+		}
+	}
+
+	if n.paramChild != nil || len(n.regexChildren) > 0 {
+		end := strings.IndexByte(rest, '/')
+		if end == -1 {
+			end = len(rest)
+		}
+		for _, child := range n.regexChildren {
+			if s, ok := walkFoldParam(child, rest, end, out); ok {
+				return s, true
+			}
+		}
+		if n.paramChild != nil {
+			if s, ok := walkFoldParam(n.paramChild, rest, end, out); ok {
+				return s, true
+			}
+		}
+	}
+
+	if n.wildcardChild != nil && n.wildcardChild.route != nil {
+		return string(append(out, rest...)), true
+	}
+	return "", false
+}
+
+// walkFoldParam is walkParam for walkFold.
+func walkFoldParam(child *node, rest string, end int, out []byte) (string, bool) {
+	if child.inSegment {
+		for k := end - 1; k > 0; k-- {
+			c := rest[k]
+			if child.staticChildIndex(toLowerASCII(c)) < 0 && child.staticChildIndex(toUpperASCII(c)) < 0 {
+				continue
+			}
+			if !child.matchValue(rest[:k]) {
+				continue
+			}
+			if s, ok := walkFold(child, rest[k:], append(out, rest[:k]...)); ok {
+				return s, true
+			}
+		}
+	}
+	if !child.matchValue(rest[:end]) {
+		return "", false
+	}
+	out = append(out, rest[:end]...)
+	if end == len(rest) {
+		return string(out), child.route != nil
+	}
+	return walkFold(child, rest[end:], out)
+}
+
+func toLowerASCII(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+func toUpperASCII(b byte) byte {
+	if 'a' <= b && b <= 'z' {
+		return b - ('a' - 'A')
+	}
+	return b
+}
+
 // bumpAlongPath walks from root following path and increments priority on
 // each visited node, then re-sorts each parent's static children by
 // priority desc to keep hot paths at the front of indices/children.
@@ -363,16 +666,33 @@ func (t *radixTree) bumpAlongPath(path string) {
 		}
 		c := remaining[0]
 		switch c {
+		case '{':
+			end := closingBrace(remaining, 0)
+			if end == -1 {
+				return
+			}
+			name, pattern := splitParamDef(remaining[1:end])
+			var next *node
+			if pattern == "" {
+				next = n.paramChild
+			}
+			for _, child := range n.regexChildren {
+				if child.pattern == pattern && child.paramName == name {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				return
+			}
+			n = next
+			remaining = remaining[end+1:]
 		case ':':
 			if n.paramChild == nil {
 				return
 			}
 			n = n.paramChild
-			end := strings.IndexByte(remaining, '/')
-			if end == -1 {
-				end = len(remaining)
-			}
-			remaining = remaining[end:]
+			remaining = remaining[paramNameEnd(remaining, 1):]
 		case '*':
 			if n.wildcardChild == nil {
 				return
@@ -394,6 +714,36 @@ func (t *radixTree) bumpAlongPath(path string) {
 	}
 }
 
+// clone returns a deep copy of t, sharing no node or variant slot with it.
+func (t *radixTree) clone() *radixTree {
+	return &radixTree{root: t.root.clone(), maxParams: t.maxParams}
+}
+
+func (n *node) clone() *node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.indices = slices.Clone(n.indices)
+	c.children = cloneNodes(n.children)
+	c.regexChildren = cloneNodes(n.regexChildren)
+	c.paramChild = n.paramChild.clone()
+	c.wildcardChild = n.wildcardChild.clone()
+	c.route = cloneSlot(n.route)
+	return &c
+}
+
+func cloneNodes(nodes []*node) []*node {
+	if nodes == nil {
+		return nil
+	}
+	out := make([]*node, len(nodes))
+	for i, n := range nodes {
+		out[i] = n.clone()
+	}
+	return out
+}
+
 // walk traverses depth-first, yielding each leaf's reconstructed path.
 // Used by HEAD-mirror logic to enumerate all dynamic routes.
 func (t *radixTree) walk(fn func(path string, leaf *node)) {
@@ -406,7 +756,15 @@ func walkFrom(n *node, accumulated string, fn func(string, *node)) {
 		fn(here, n)
 	}
 	for _, child := range n.children {
-		walkFrom(child, here, fn)
+		base := here
+		if n.nType == nodeParam && n.pattern == "" && child.prefix[0] != '/' {
+			// A literal continues the segment, which ":name" would take in.
+			base = strings.TrimSuffix(here, ":"+n.paramName) + "{" + n.paramName + "}"
+		}
+		walkFrom(child, base, fn)
+	}
+	for _, child := range n.regexChildren {
+		walkFrom(child, here+"{"+child.paramName+":"+child.pattern+"}", fn)
 	}
 	if n.paramChild != nil {
 		// Reconstruct ":name" segment for the path so insert() can parse it.
@@ -202,3 +202,134 @@ func TestTree_PrioritySortsChildren(t *testing.T) {
 	assert.Eq(t, byte('b'), tree.root.indices[0])
 	assert.Eq(t, byte('a'), tree.root.indices[1])
 }
+
+func TestTreeLookup_ConstrainedParam(t *testing.T) {
+	tree := newRadixTree()
+	h := func(c *Context) {}
+	r := newRoute(`/users/{id:\d+}`, h, []string{GET})
+	tree.insert(`/users/{id:\d+}`, r)
+
+	var ps Params
+	got, ok := tree.lookup("/users/42", &ps)
+	assert.True(t, ok)
+	assert.Same(t, r, got)
+	assert.Eq(t, "42", ps.Get("id"))
+
+	ps.Reset()
+	_, ok = tree.lookup("/users/abc", &ps)
+	assert.False(t, ok)
+	assert.Eq(t, 0, ps.Len())
+}
+
+func TestTreeLookup_ConstrainedSiblings(t *testing.T) {
+	tree := newRadixTree()
+	h := func(c *Context) {}
+	rID := newRoute(`/p/{id:\d+}`, h, []string{GET})
+	rSlug := newRoute(`/p/{slug:[a-z-]+}`, h, []string{GET})
+	rAny := newRoute("/p/:key", h, []string{GET})
+	tree.insert("/p/:key", rAny)
+	tree.insert(`/p/{id:\d+}`, rID)
+	tree.insert(`/p/{slug:[a-z-]+}/edit`, rSlug)
+	tree.insert(`/p/{slug:[a-z-]+}`, rSlug)
+
+	var ps Params
+	got, _ := tree.lookup("/p/12", &ps)
+	assert.Same(t, rID, got)
+	assert.Eq(t, "12", ps.Get("id"))
+
+	ps.Reset()
+	got, _ = tree.lookup("/p/hello-world", &ps)
+	assert.Same(t, rSlug, got)
+	assert.Eq(t, "hello-world", ps.Get("slug"))
+
+	// Neither constraint matches — the plain param catches it.
+	ps.Reset()
+	got, _ = tree.lookup("/p/X_1", &ps)
+	assert.Same(t, rAny, got)
+	assert.Eq(t, "X_1", ps.Get("key"))
+}
+
+func TestTreeInsert_ConstrainedConflicts(t *testing.T) {
+	tree := newRadixTree()
+	h := func(c *Context) {}
+	tree.insert(`/u/{id:\d+}`, newRoute(`/u/{id:\d+}`, h, []string{GET}))
+	// Same pattern under another name is ambiguous.
+	assert.Panics(t, func() {
+		tree.insert(`/u/{num:\d+}/x`, newRoute(`/u/{num:\d+}/x`, h, []string{GET}))
+	})
+	assert.Panics(t, func() {
+		tree.insert(`/v/{id:[}`, newRoute(`/v/{id:[}`, h, []string{GET}))
+	})
+	// Adjacent params have no literal telling where one value ends.
+	assert.Panics(t, func() {
+		tree.insert(`/w/{id:\d+}{ext}`, newRoute(`/w/{id:\d+}{ext}`, h, []string{GET}))
+	})
+	assert.Panics(t, func() {
+		tree.insert("/w/{a}:b", newRoute("/w/{a}:b", h, []string{GET}))
+	})
+}
+
+func TestTreeWalk_ReconstructsConstrainedPath(t *testing.T) {
+	tree := newRadixTree()
+	h := func(c *Context) {}
+	tree.insert(`/u/{id:\d+}/posts`, newRoute(`/u/{id:\d+}/posts`, h, []string{GET}))
+	assert.True(t, tree.hasExact(`/u/{id:\d+}/posts`))
+	assert.Eq(t, uint8(1), tree.maxParams)
+}
+
+func TestTreeLookup_MixedSegments(t *testing.T) {
+	tree := newRadixTree()
+	h := func(c *Context) {}
+	paths := []string{
+		"/files/{name}.:ext",
+		"/files/{name}.txt",
+		"/files/:name",
+		"/v{major}.:minor/status",
+		"/@:user",
+		`/img/{w:\d+}x{h:\d+}.png`,
+	}
+	routes := make(map[string]*Route)
+	for _, p := range paths {
+		routes[p] = newRoute(p, h, []string{GET})
+		tree.insert(p, routes[p])
+	}
+
+	cases := []struct {
+		path, route string
+		params      map[string]string
+	}{
+		// The longest name leaving a matching literal wins.
+		{"/files/a.tar.gz", "/files/{name}.:ext", map[string]string{"name": "a.tar", "ext": "gz"}},
+		// Literals beat params.
+		{"/files/notes.txt", "/files/{name}.txt", map[string]string{"name": "notes"}},
+		{"/files/README", "/files/:name", map[string]string{"name": "README"}},
+		// Values are never empty: the whole segment is the name.
+		{"/files/.env", "/files/:name", map[string]string{"name": ".env"}},
+		{"/files/x.", "/files/:name", map[string]string{"name": "x."}},
+		{"/v1.2/status", "/v{major}.:minor/status", map[string]string{"major": "1", "minor": "2"}},
+		{"/@alice", "/@:user", map[string]string{"user": "alice"}},
+		{"/img/640x480.png", `/img/{w:\d+}x{h:\d+}.png`, map[string]string{"w": "640", "h": "480"}},
+	}
+	for _, tc := range cases {
+		var ps Params
+		got, ok := tree.lookup(tc.path, &ps)
+		assert.True(t, ok, tc.path)
+		assert.Same(t, routes[tc.route], got, tc.path)
+		assert.Eq(t, len(tc.params), ps.Len(), tc.path)
+		for k, v := range tc.params {
+			assert.Eq(t, v, ps.Get(k), tc.path)
+		}
+	}
+
+	for _, miss := range []string{"/v1/status", "/v1.2", "/img/axb.png", "/img/640x480.gif", "/@"} {
+		var ps Params
+		_, ok := tree.lookup(miss, &ps)
+		assert.False(t, ok, miss)
+		assert.Eq(t, 0, ps.Len(), miss)
+	}
+
+	fixed, ok := tree.findFold("/V1.2/STATUS")
+	assert.True(t, ok)
+	assert.Eq(t, "/v1.2/status", fixed)
+	assert.True(t, tree.hasExact("/v{major}.:minor/status"))
+}
@@ -0,0 +1,203 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/gookit/goutil"
+	"github.com/gookit/goutil/netutil/httpreq"
+	"github.com/gookit/rux/v2/pkg/binding"
+	"github.com/gookit/rux/v2/pkg/render"
+)
+
+// StatusCoder is implemented by errors (and responses) that carry an
+// HTTP status code. Typed uses it to pick the response status.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Typed adapts fn to a HandlerFunc that binds the request into a new In,
+// validates it, calls fn and renders the returned Out.
+//
+// In is filled from, in order: the request body (POST, PUT, PATCH; by
+// Content-Type, see binding.DecodeBody), query params (query:"..." tags),
+// headers (header:"..." tags) and route path params (path:"..." tags).
+// Query, header and path params are only bound if In has fields with the
+// matching tag. binding.Validate runs once In is filled.
+//
+// Errors fail the request (see Context.Fail) and are rendered by the
+// OnError hook of the route, its groups or the router, by default as
+// problem details (see ProblemHandler): bind failures are 400, validation
+// failures 422 (see Router.MapBindError, MapValidationError), errors with
+// a StatusCoder use its status and anything else is 500.
+//
+// Out is rendered as JSON, XML or text by the request Accept header
+// (JSON by default) with status 200, or the status of Out if it
+// implements StatusCoder. A nil Out renders 204 No Content.
+//
+//	r.GET("/users/{id}", rux.Typed(func(ctx context.Context, in *GetUser) (*User, error) {
+//	    return repo.Find(ctx, in.ID)
+//	}))
+func Typed[In, Out any](fn func(ctx context.Context, in *In) (*Out, error)) HandlerFunc {
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	tags := structTags(inType)
+
+	return func(c *Context) {
+		in := new(In)
+		if err := bindTyped(c, in, tags); err != nil {
+			c.Fail(err)
+			return
+		}
+
+		out, err := fn(c.Req.Context(), in)
+		if err != nil {
+			c.Fail(err)
+			return
+		}
+		if out == nil {
+			c.NoContent()
+			return
+		}
+
+		status := http.StatusOK
+		if sc, ok := any(out).(StatusCoder); ok {
+			status = sc.StatusCode()
+		}
+		renderNegotiated(c, status, out)
+	}
+}
+
+// RouteAdder registers routes, it is implemented by Router and
+// RouteGroup.
+type RouteAdder interface {
+	Add(path string, handler HandlerFunc, methods ...string) *Route
+}
+
+// AddTyped registers fn, adapted by Typed, on path for methods, and
+// records In and Out on the route for documentation tooling, see
+// Route.TypedIO. The route's HandlerName is the name of fn.
+//
+//	rux.AddTyped(r, "/users/{id}", getUser, rux.GET)
+func AddTyped[In, Out any](ra RouteAdder, path string, fn func(ctx context.Context, in *In) (*Out, error),
+	methods ...string) *Route {
+	route := ra.Add(path, Typed(fn), methods...)
+	io := &typedIOTypes{in: reflect.TypeOf((*In)(nil)).Elem(), out: reflect.TypeOf((*Out)(nil)).Elem()}
+	route.io = io
+	for _, exp := range route.expansions {
+		exp.io = io
+	}
+	route.setHandlerName(goutil.FuncName(fn))
+	return route
+}
+
+// TypedIO returns the request and response types of a route registered
+// by AddTyped, for documentation tooling.
+func (r *Route) TypedIO() (in, out reflect.Type, ok bool) {
+	if r.io == nil {
+		return nil, nil, false
+	}
+	return r.io.in, r.io.out, true
+}
+
+type typedIOTypes struct {
+	in, out reflect.Type
+}
+
+// bindTags reports which binding tags a request struct uses.
+type bindTags struct {
+	query, header, path bool
+}
+
+// structTags collects the binding tags used by the fields of t, including
+// embedded structs.
+func structTags(t reflect.Type) (tags bindTags) {
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tags.query = tags.query || f.Tag.Get(binding.QueryTagName) != ""
+		tags.header = tags.header || f.Tag.Get(binding.HeaderTagName) != ""
+		tags.path = tags.path || f.Tag.Get(binding.PathTagName) != ""
+		if f.Anonymous {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			sub := structTags(ft)
+			tags.query = tags.query || sub.query
+			tags.header = tags.header || sub.header
+			tags.path = tags.path || sub.path
+		}
+	}
+	return
+}
+
+// bindTyped fills in from the request and validates it.
+func bindTyped(c *Context, in any, tags bindTags) error {
+	req := c.Req
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if req.ContentLength != 0 && req.Body != nil && req.Body != http.NoBody {
+			if err := binding.DecodeBody(req, in); err != nil {
+				return err
+			}
+		}
+	}
+
+	if tags.query {
+		if err := binding.DecodeValues(req.URL.Query(), in, binding.QueryTagName); err != nil {
+			return err
+		}
+	}
+	if tags.header {
+		if err := binding.DecodeValues(req.Header, in, binding.HeaderTagName); err != nil {
+			return err
+		}
+	}
+	if tags.path && c.params.n > 0 {
+		values := make(url.Values, c.params.n)
+		for _, p := range c.params.data[:c.params.n] {
+			values.Set(p.Key, p.Value)
+		}
+		if err := binding.DecodeValues(values, in, binding.PathTagName); err != nil {
+			return err
+		}
+	}
+
+	return binding.Validate(in)
+}
+
+// renderNegotiated renders v as JSON, XML or text by the Accept header.
+func renderNegotiated(c *Context, status int, v any) {
+	for _, accept := range httpreq.ParseAccept(c.Req.Header.Get("Accept")) {
+		switch {
+		case accept == "application/json" || strings.HasSuffix(accept, "+json"):
+			c.Respond(status, v, render.JSONRenderer{})
+			return
+		case accept == "application/xml" || accept == "text/xml":
+			c.Respond(status, v, render.XMLRenderer{})
+			return
+		case accept == "text/plain":
+			c.Text(status, plainText(v))
+			return
+		case accept == "*/*" || accept == "application/*":
+			c.Respond(status, v, render.JSONRenderer{})
+			return
+		}
+	}
+	c.Respond(status, v, render.JSONRenderer{})
+}
+
+// plainText returns the text form of the response v: its String method,
+// else the value v points to, formatted by fmt.
+func plainText(v any) string {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(reflect.Indirect(reflect.ValueOf(v)).Interface())
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+type typedReq struct {
+	ID      int    `path:"id"`
+	Verbose bool   `query:"verbose"`
+	TraceID string `header:"X-Trace-Id"`
+	Name    string `json:"name"`
+}
+
+type typedResp struct {
+	ID      int    `json:"id" xml:"id"`
+	Name    string `json:"name" xml:"name"`
+	Verbose bool   `json:"verbose" xml:"verbose"`
+	TraceID string `json:"trace" xml:"trace"`
+}
+
+type createdResp struct {
+	ID int `json:"id"`
+}
+
+func (createdResp) StatusCode() int { return http.StatusCreated }
+
+func (c *createdResp) String() string { return "item " + strconv.Itoa(c.ID) }
+
+type teapotErr struct{}
+
+func (teapotErr) Error() string   { return "short and stout" }
+func (teapotErr) StatusCode() int { return http.StatusTeapot }
+
+func echoTyped(_ context.Context, in *typedReq) (*typedResp, error) {
+	return &typedResp{ID: in.ID, Name: in.Name, Verbose: in.Verbose, TraceID: in.TraceID}, nil
+}
+
+func typedRequest(r *Router, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestTyped_BindsAllSources(t *testing.T) {
+	r := New()
+	r.PUT("/users/{id}", Typed(echoTyped))
+
+	w := typedRequest(r, PUT, "/users/42?verbose=true", `{"name":"tom","ID":7}`,
+		"Content-Type", "application/json", "X-Trace-Id", "abc")
+	assert.Eq(t, 200, w.Code)
+	assert.StrContains(t, w.Header().Get(ContentType), "application/json")
+	// Path params win over the body.
+	assert.Eq(t, `{"id":42,"name":"tom","verbose":true,"trace":"abc"}`, strings.TrimSpace(w.Body.String()))
+}
+
+func TestTyped_ContentNegotiation(t *testing.T) {
+	r := New()
+	r.GET("/users/{id}", Typed(echoTyped))
+	r.GET("/items/{id}", Typed(func(context.Context, *struct{}) (*createdResp, error) {
+		return &createdResp{ID: 3}, nil
+	}))
+
+	w := typedRequest(r, GET, "/users/1", "", "Accept", "application/xml")
+	assert.Eq(t, 200, w.Code)
+	assert.StrContains(t, w.Body.String(), "<id>1</id>")
+
+	w = typedRequest(r, GET, "/users/1", "", "Accept", "text/html, */*;q=0.8")
+	assert.StrContains(t, w.Header().Get(ContentType), "application/json")
+
+	w = typedRequest(r, GET, "/users/1", "", "Accept", "text/plain")
+	assert.StrContains(t, w.Header().Get(ContentType), "text/plain")
+	assert.Eq(t, "{1  false }", w.Body.String())
+	w = typedRequest(r, GET, "/items/3", "", "Accept", "text/plain")
+	assert.Eq(t, "item 3", w.Body.String())
+}
+
+func TestTyped_Errors(t *testing.T) {
+	var gotErr error
+	r := New()
+	r.OnError = func(c *Context) {
+		gotErr = c.FirstError()
+		ProblemHandler(c)
+	}
+	r.POST("/bad", Typed(echoTyped))
+	r.GET("/teapot", Typed(func(context.Context, *struct{}) (*typedResp, error) {
+		return nil, teapotErr{}
+	}))
+	r.GET("/boom", Typed(func(context.Context, *struct{}) (*typedResp, error) {
+		return nil, errors.New("db password leaked")
+	}))
+
+	w := typedRequest(r, POST, "/bad", `{"name":`, "Content-Type", "application/json")
+	assert.Eq(t, 400, w.Code)
+	assert.NotNil(t, gotErr)
+
+	w = typedRequest(r, GET, "/teapot", "")
+	assert.Eq(t, http.StatusTeapot, w.Code)
+	assert.Eq(t, ProblemJSON, w.Header().Get(ContentType))
+	assert.StrContains(t, w.Body.String(), `"detail":"short and stout"`)
+
+	w = typedRequest(r, GET, "/boom", "", "Accept", "text/plain")
+	assert.Eq(t, 500, w.Code)
+	assert.Eq(t, "500 Internal Server Error", w.Body.String())
+	assert.Eq(t, "db password leaked", gotErr.Error())
+}
+
+// The error of a Typed handler is rendered once, by the OnError hook.
+func TestTyped_ErrorHook(t *testing.T) {
+	r := New()
+	g := r.NewGroup("/api").OnError(func(c *Context) { c.HTML(500, []byte("<h1>oops</h1>")) })
+	g.GET("/boom", Typed(func(context.Context, *struct{}) (*typedResp, error) {
+		return nil, errors.New("boom")
+	}))
+
+	w := typedRequest(r, GET, "/api/boom", "")
+	assert.Eq(t, 500, w.Code)
+	assert.Eq(t, "<h1>oops</h1>", w.Body.String())
+}
+
+func TestTyped_Validation(t *testing.T) {
+	withMockValidator(t, &mockValidator{err: errors.New("name is required")})
+	r := New()
+	r.POST("/users", Typed(echoTyped))
+
+	w := typedRequest(r, POST, "/users", `{}`, "Content-Type", "application/json")
+	assert.Eq(t, http.StatusUnprocessableEntity, w.Code)
+	assert.StrContains(t, w.Body.String(), "name is required")
+}
+
+func TestTyped_StatusAndNoContent(t *testing.T) {
+	r := New()
+	r.POST("/items", Typed(func(context.Context, *struct{}) (*createdResp, error) {
+		return &createdResp{ID: 9}, nil
+	}))
+	r.DELETE("/items/{id}", Typed(func(context.Context, *struct{}) (*struct{}, error) {
+		return nil, nil
+	}))
+
+	w := typedRequest(r, POST, "/items", "")
+	assert.Eq(t, http.StatusCreated, w.Code)
+	assert.Eq(t, `{"id":9}`, strings.TrimSpace(w.Body.String()))
+
+	w = typedRequest(r, DELETE, "/items/9", "")
+	assert.Eq(t, http.StatusNoContent, w.Code)
+	assert.Eq(t, "", w.Body.String())
+}
+
+func TestRoute_TypedIO(t *testing.T) {
+	r := New()
+	r1 := AddTyped(r, "/users/{id}", echoTyped)
+	r2 := AddTyped(r.NewGroup("/v1"), "/items[/{id}]", func(context.Context, *struct{}) (*createdResp, error) {
+		return nil, nil
+	}, POST)
+	assert.Eq(t, []string{POST}, r2.Methods())
+
+	in, out, ok := r1.TypedIO()
+	assert.True(t, ok)
+	assert.Eq(t, reflect.TypeOf(typedReq{}), in)
+	assert.Eq(t, reflect.TypeOf(typedResp{}), out)
+
+	_, out, ok = r2.TypedIO()
+	assert.True(t, ok)
+	assert.Eq(t, reflect.TypeOf(createdResp{}), out)
+
+	// Typed alone records nothing.
+	_, _, ok = r.GET("/x", Typed(echoTyped)).TypedIO()
+	assert.False(t, ok)
+	_, _, ok = r.GET("/y", textHandler("y")).TypedIO()
+	assert.False(t, ok)
+
+	// The optional segment copies matched by requests have them too.
+	matched, _, _ := r.Match(POST, "/v1/items/3")
+	_, out, ok = matched.TypedIO()
+	assert.True(t, ok)
+	assert.Eq(t, reflect.TypeOf(createdResp{}), out)
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gookit/goutil"
+)
+
+// ErrUnknownRoute is wrapped by the error of URLFor for a name no route is
+// registered under.
+var ErrUnknownRoute = errors.New("unknown route")
+
+// URLFor builds the URL of the named route from params, see Route.URL.
+// Unlike BuildURL, it returns an error instead of a URL with missing or
+// invalid params:
+//
+//	u, err := r.URLFor("user_show", rux.M{"id": 7, "tab": "posts"}) // /users/7?tab=posts
+func (r *Router) URLFor(name string, params M) (*url.URL, error) {
+	route := r.GetRoute(name)
+	if route == nil {
+		return nil, fmt.Errorf("rux: URLFor %q: %w", name, ErrUnknownRoute)
+	}
+	u, err := route.URL(params)
+	if err != nil {
+		return nil, fmt.Errorf("rux: URLFor %q: %w", name, err)
+	}
+	return u, nil
+}
+
+// URL builds the URL of the route from params, keyed by param name. Path
+// and host params fill their placeholders, other params become query
+// params.
+//
+// Param values must match their constraint regex and are path-escaped; a
+// wildcard value is escaped segment by segment, so it keeps its slashes.
+// For a route with an optional segment, the segment is built when all of
+// its params are set. A missing param or a value not matching its regex
+// is returned as a *ParamError.
+func (r *Route) URL(params M) (*url.URL, error) {
+	tpl := r.originalPath
+	if tpl == "" {
+		tpl = r.path
+	}
+	if start, end := optionalSegmentBounds(tpl); start >= 0 && end > start {
+		inner := tpl[start+1 : end]
+		if hasTemplateParams(inner) && allParamsSet(inner, params) {
+			tpl = tpl[:start] + inner + tpl[end+1:]
+		} else {
+			tpl = tpl[:start] + tpl[end+1:]
+		}
+	}
+
+	used := make(map[string]bool, len(params))
+	escaped, err := fillTemplate(tpl, params, used, true)
+	if err != nil {
+		return nil, err
+	}
+	u := &url.URL{RawPath: escaped}
+	// escaped was built by fillTemplate, so it always unescapes.
+	u.Path, _ = url.PathUnescape(escaped)
+
+	// Wildcard host patterns cannot be built and are left out.
+	if r.host != "" && strings.IndexByte(r.host, '*') == -1 {
+		if u.Host, err = fillTemplate(r.host, params, used, false); err != nil {
+			return nil, err
+		}
+	}
+
+	query := make(url.Values)
+	for k, v := range params {
+		if used[k] {
+			continue
+		}
+		if vs, ok := v.([]string); ok {
+			query[k] = vs
+		} else {
+			query.Add(k, goutil.String(v))
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u, nil
+}
+
+// TemplateFuncs returns functions for views to link by route name, to be
+// added to an html/template or text/template with Funcs:
+//
+//	url: {{ url "user_show" "id" .User.ID "tab" "posts" }}
+//
+// url builds the URL of a route from param name and value pairs, see
+// URLFor; template execution fails on its errors.
+func (r *Router) TemplateFuncs() map[string]any {
+	return map[string]any{"url": r.templateURL}
+}
+
+func (r *Router) templateURL(name string, pairs ...any) (string, error) {
+	if len(pairs)%2 == 1 {
+		return "", fmt.Errorf("rux: url %q: odd argument count for name, value pairs", name)
+	}
+	params := make(M, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		params[fmt.Sprint(pairs[i])] = pairs[i+1]
+	}
+	u, err := r.URLFor(name, params)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// fillTemplate replaces the {name}, {name:regex}, :name and *name
+// placeholders of the route path or host pattern tpl with their escaped
+// params, recording them in used. As in route paths, a :name runs to the
+// next '/'; isPath is false for host patterns, where ':' starts the port.
+func fillTemplate(tpl string, params M, used map[string]bool, isPath bool) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(tpl); i++ {
+		var name, regex string
+		switch tpl[i] {
+		case '{':
+			end := closingBrace(tpl, i)
+			if end == -1 {
+				sb.WriteByte(tpl[i])
+				continue
+			}
+			name, regex = splitParamDef(tpl[i+1 : end])
+			i = end
+		case ':':
+			if !isPath {
+				sb.WriteByte(tpl[i])
+				continue
+			}
+			end := paramNameEnd(tpl, i+1)
+			name = tpl[i+1 : end]
+			i = end - 1
+		case '*':
+			end := i + 1
+			for end < len(tpl) && tpl[end] != '/' {
+				end++
+			}
+			name, regex = tpl[i+1:end], ".*"
+			i = end - 1
+		default:
+			sb.WriteByte(tpl[i])
+			continue
+		}
+
+		v, ok := params[name]
+		val := goutil.String(v)
+		if !ok || val == "" && regex != ".*" {
+			return "", &ParamError{Name: name, Err: ErrParamMissing}
+		}
+		used[name] = true
+		switch regex {
+		case ".+", ".*":
+			segs := strings.Split(val, "/")
+			for j, seg := range segs {
+				segs[j] = url.PathEscape(seg)
+			}
+			sb.WriteString(strings.Join(segs, "/"))
+			continue
+		case "":
+		default:
+			if !paramRegexp(regex).MatchString(val) {
+				return "", &ParamError{Name: name, Value: val, Err: fmt.Errorf("does not match %s", regex)}
+			}
+		}
+		sb.WriteString(url.PathEscape(val))
+	}
+	return sb.String(), nil
+}
+
+// hasTemplateParams reports whether the path template tpl has params.
+func hasTemplateParams(tpl string) bool {
+	return strings.ContainsAny(tpl, "{:*")
+}
+
+// allParamsSet reports whether params sets every param of the path
+// template tpl to a non-empty value.
+func allParamsSet(tpl string, params M) bool {
+	_, err := fillTemplate(tpl, params, make(map[string]bool), true)
+	var pe *ParamError
+	return !errors.As(err, &pe) || !errors.Is(pe, ErrParamMissing)
+}
+
+// paramRegexps caches the compiled constraint regexes of URL.
+var paramRegexps sync.Map
+
+// paramRegexp returns the compiled regex of a param constraint, anchored
+// like the tree's.
+func paramRegexp(pattern string) *regexp.Regexp {
+	if re, ok := paramRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile("^(?:" + pattern + ")$")
+	paramRegexps.Store(pattern, re)
+	return re
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestRouter_URLFor(t *testing.T) {
+	r := New()
+	noop := func(c *Context) {}
+	r.AddNamed("user", `/users/{id:\d+}`, noop)
+	r.AddNamed("file", "/files/*path", noop)
+	r.AddNamed("asset", "/assets/{path:.+}", noop)
+	r.AddNamed("post", "/posts[/{slug}]", noop)
+	r.AddNamed("page", "/pages/{name}[.html]", noop)
+	r.AddNamed("search", "/search/{q}", noop)
+	r.Host("{tenant}.example.com", func() {
+		r.AddNamed("dashboard", "/dashboard", noop)
+	})
+
+	u, err := r.URLFor("user", M{"id": 7, "tab": "posts"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/users/7?tab=posts", u.String())
+
+	u, err = r.URLFor("file", M{"path": "docs/a b/c.txt"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/files/docs/a%20b/c.txt", u.String())
+	assert.Eq(t, "/files/docs/a b/c.txt", u.Path)
+	u, err = r.URLFor("file", M{"path": ""})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/files/", u.String())
+
+	u, err = r.URLFor("asset", M{"path": "css/app.css"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/assets/css/app.css", u.String())
+
+	u, err = r.URLFor("post", M{"slug": "hello"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/posts/hello", u.String())
+	u, err = r.URLFor("post", nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, "/posts", u.String())
+
+	u, err = r.URLFor("page", M{"name": "about"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/pages/about", u.String())
+
+	u, err = r.URLFor("search", M{"q": "a/b?c"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/search/a%2Fb%3Fc", u.String())
+
+	u, err = r.URLFor("dashboard", M{"tenant": "acme"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "//acme.example.com/dashboard", u.String())
+}
+
+func TestRouter_URLFor_ColonParams(t *testing.T) {
+	r := New()
+	noop := func(c *Context) {}
+	r.AddNamed("member", "/members/:id/posts[/:post-id]", noop)
+	r.Host("api.example.com:8080", func() {
+		r.AddNamed("status", "/status/:code", noop)
+	})
+
+	u, err := r.URLFor("member", M{"id": 5})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/members/5/posts", u.String())
+	u, err = r.URLFor("member", M{"id": 5, "post-id": "a b"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "/members/5/posts/a%20b", u.String())
+
+	// The host port is not a param.
+	u, err = r.URLFor("status", M{"code": 200})
+	assert.NoErr(t, err)
+	assert.Eq(t, "//api.example.com:8080/status/200", u.String())
+
+	_, err = r.URLFor("member", M{"post-id": 1})
+	assert.True(t, errors.Is(err, ErrParamMissing))
+}
+
+func TestRouter_URLFor_Errors(t *testing.T) {
+	r := New()
+	r.AddNamed("user", `/users/{id:\d+}`, func(c *Context) {})
+
+	_, err := r.URLFor("missing", nil)
+	assert.True(t, errors.Is(err, ErrUnknownRoute))
+	assert.Eq(t, `rux: URLFor "missing": unknown route`, err.Error())
+
+	_, err = r.URLFor("user", nil)
+	var pe *ParamError
+	assert.True(t, errors.As(err, &pe))
+	assert.True(t, errors.Is(err, ErrParamMissing))
+	assert.Eq(t, `rux: URLFor "user": path param "id" is missing`, err.Error())
+
+	_, err = r.URLFor("user", M{"id": "abc"})
+	assert.Eq(t, `rux: URLFor "user": path param "id": invalid value "abc": does not match \d+`, err.Error())
+}
+
+func TestRouter_TemplateFuncs(t *testing.T) {
+	r := New()
+	r.AddNamed("user", `/users/{id:\d+}`, func(c *Context) {})
+
+	tpl := template.Must(template.New("").Funcs(r.TemplateFuncs()).Parse(
+		`<a href="{{ url "user" "id" .ID "tab" "a&b" }}">user</a>`))
+	var buf bytes.Buffer
+	assert.NoErr(t, tpl.Execute(&buf, map[string]any{"ID": 7}))
+	assert.Eq(t, `<a href="/users/7?tab=a%26b">user</a>`, buf.String())
+
+	buf.Reset()
+	assert.Err(t, tpl.Execute(&buf, map[string]any{"ID": "x"}))
+}
@@ -90,64 +90,128 @@ func hasOptionalSegment(path string) bool {
 // {"/posts", "/posts/:id"}. Always returns at least one element.
 // Caller must have validated the path with util.ValidateOptionalSegments first.
 func parseOptionalSegments(path string) []string {
-	start := strings.IndexByte(path, '[')
-	end := strings.IndexByte(path, ']')
+	start, end := optionalSegmentBounds(path)
 	if start < 0 || end < 0 {
 		return []string{convertParamSyntax(path)}
 	}
 
-	before := convertParamSyntax(path[:start])
-	inner := convertParamSyntax(path[start+1 : end])
-	after := ""
-	if end+1 < len(path) {
-		after = convertParamSyntax(path[end+1:])
+	// Converted whole: whether "{name}" keeps its braces depends on what
+	// follows it in the expanded path.
+	before, inner, after := path[:start], path[start+1:end], path[end+1:]
+	return []string{convertParamSyntax(before + after), convertParamSyntax(before + inner + after)}
+}
+
+// optionalSegmentBounds returns the indexes of the first '[' and its ']'
+// outside brace-quoted regexes, or -1 for either when absent.
+func optionalSegmentBounds(path string) (start, end int) {
+	start, end = -1, -1
+	depth := 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '[':
+			if depth == 0 && start < 0 {
+				start = i
+			}
+		case ']':
+			if depth == 0 && start >= 0 {
+				return start, i
+			}
+		}
 	}
-	return []string{before + after, before + inner + after}
+	return start, end
 }
 
-// convertParamSyntax rewrites Rux's brace param syntax to colon syntax.
+// convertParamSyntax rewrites Rux's brace param syntax to the tree syntax.
 //
 //	{id}        -> :id
-//	{id:\d+}    -> :id          (regex stripped — see P-14)
+//	{id}.json   -> {id}.json   (a literal follows — kept for the radix tree)
+//	{id:\d+}    -> {id:\d+}    (constrained — kept for the radix tree)
 //	{file:.+}   -> *file        (catch-all)
 //	{file:.*}   -> *file
 func convertParamSyntax(path string) string {
-	for {
-		start := strings.IndexByte(path, '{')
+	for i := 0; i < len(path); {
+		start := strings.IndexByte(path[i:], '{')
 		if start == -1 {
 			return path
 		}
-		// Find matching '}' with brace counting (regex may contain '{1,2}').
-		depth := 0
-		end := -1
-		for i := start; i < len(path); i++ {
-			switch path[i] {
-			case '{':
-				depth++
-			case '}':
-				depth--
-				if depth == 0 {
-					end = i
-				}
-			}
-			if end != -1 {
-				break
-			}
-		}
+		start += i
+		end := closingBrace(path, start)
 		if end == -1 {
 			return path
 		}
 
-		content := path[start+1 : end]
-		name := content
-		if colon := strings.IndexByte(content, ':'); colon > 0 {
-			name = strings.TrimSpace(content[:colon])
-			regex := strings.TrimSpace(content[colon+1:])
-			if regex == ".+" || regex == ".*" {
-				path = path[:start] + "*" + name + path[end+1:]
-				continue
+		name, regex := splitParamDef(path[start+1 : end])
+		switch {
+		case regex == "" && end+1 < len(path) && path[end+1] != '/':
+			i = end + 1
+		case regex == "":
+			path = path[:start] + ":" + name + path[end+1:]
+			i = start + 1 + len(name)
+		case regex == ".+" || regex == ".*":
+			path = path[:start] + "*" + name + path[end+1:]
+			i = start + 1 + len(name)
+		default:
+			def := "{" + name + ":" + regex + "}"
+			path = path[:start] + def + path[end+1:]
+			i = start + len(def)
+		}
+	}
+	return path
+}
+
+// closingBrace returns the index of the '}' matching the '{' at s[start],
+// counting nested braces (a regex may contain quantifiers like '{1,2}').
+// Returns -1 if the brace is unbalanced.
+func closingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitParamDef splits the inside of a brace param ("id:\d+") into its
+// name and regex. regex is "" for an unconstrained param.
+func splitParamDef(content string) (name, regex string) {
+	if colon := strings.IndexByte(content, ':'); colon > 0 {
+		return strings.TrimSpace(content[:colon]), strings.TrimSpace(content[colon+1:])
+	}
+	return strings.TrimSpace(content), ""
+}
+
+// parseConstraints collects the regex constraint of every constrained
+// param in a brace-syntax path. Catch-all regexes (.+ / .*) are not
+// constraints. Returns nil if the path has none.
+func parseConstraints(path string) map[string]string {
+	var out map[string]string
+	for i := 0; i < len(path); i++ {
+		if path[i] != '{' {
+			continue
+		}
+		end := closingBrace(path, i)
+		if end == -1 {
+			break
+		}
+		name, regex := splitParamDef(path[i+1 : end])
+		if regex != "" && regex != ".+" && regex != ".*" {
+			if out == nil {
+				out = make(map[string]string, 2)
 			}
+			out[name] = regex
 		}
-		path = path[:start] + ":" + name + path[end+1:]
+		i = end
 	}
+	return out
 }
@@ -68,12 +68,28 @@ func TestConvertParamSyntax(t *testing.T) {
 		"/users/{id}/posts": "/users/:id/posts",
 		"/files/{path:.+}":  "/files/*path",
 		"/files/{path:.*}":  "/files/*path",
+		// Constrained params keep their regex for the tree.
+		"/users/{id:\\d+}":             "/users/{id:\\d+}",
+		"/d/{ y : \\d{4} }/{slug}":     "/d/{y:\\d{4}}/:slug",
+		"/v/{a:[a-z]+}/{b:[0-9]{1,2}}": "/v/{a:[a-z]+}/{b:[0-9]{1,2}}",
 	}
 	for in, want := range cases {
 		assert.Eq(t, want, convertParamSyntax(in), "input=%q", in)
 	}
 }
 
+func TestParseConstraints(t *testing.T) {
+	assert.Nil(t, parseConstraints("/users/{id}"))
+	assert.Nil(t, parseConstraints("/files/{path:.+}"))
+	assert.Eq(t, map[string]string{"id": `\d+`, "y": `\d{4}`},
+		parseConstraints(`/u/{id:\d+}/{y:\d{4}}/{name}`))
+}
+
+func TestParseOptionalSegments_RegexBrackets(t *testing.T) {
+	got := parseOptionalSegments("/posts[/{id:[0-9]+}]")
+	assert.Eq(t, []string{"/posts", "/posts/{id:[0-9]+}"}, got)
+}
+
 func TestHasOptionalSegment(t *testing.T) {
 	cases := map[string]bool{
 		"/users":         false,
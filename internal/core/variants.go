@@ -0,0 +1,180 @@
+package core
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+)
+
+// VariantKey returns the key a request is assigned a route variant by:
+// requests with the same key get the same variant while the weights do
+// not change. An empty key picks a variant at random. See Router.Variant.
+type VariantKey func(*http.Request) string
+
+// ByCookie assigns variants by the value of the name cookie.
+func ByCookie(name string) VariantKey {
+	return func(req *http.Request) string {
+		if c, err := req.Cookie(name); err == nil {
+			return c.Value
+		}
+		return ""
+	}
+}
+
+// ByHeader assigns variants by the value of the name header.
+func ByHeader(name string) VariantKey {
+	name = http.CanonicalHeaderKey(name)
+	return func(req *http.Request) string { return req.Header.Get(name) }
+}
+
+// ByClientIP assigns variants by client IP, see Context.ClientIP.
+func ByClientIP(req *http.Request) string { return clientIP(req) }
+
+// Variant returns a group for weighted variants. See RouteGroup.Variant.
+func (r *Router) Variant(name string, weight int, key ...VariantKey) *RouteGroup {
+	return r.group().Variant(name, weight, key...)
+}
+
+// Variant returns a nested group with the same prefix whose routes are
+// the variant name of routes sharing their method, path and conditions,
+// dispatched to weight percent of the requests. It is meant for gradual
+// rollouts and A/B tests:
+//
+//	r.GET("/checkout", checkout)
+//	r.Variant("canary", 10, rux.ByCookie("uid")).GET("/checkout", checkoutV2)
+//
+// The route registered without Variant, or with a weight of 0, gets the
+// remaining percentage; the weights of the others must then add up to
+// 100 at most. Without such a route, weights are relative.
+//
+// A variant is picked per request, at random or, given a VariantKey, by
+// a hash of the request key, so a client keeps its variant. The key of
+// the first variant registered with one is used. Context.Variant reports
+// the variant that ran.
+func (g *RouteGroup) Variant(name string, weight int, key ...VariantKey) *RouteGroup {
+	if name == "" {
+		panic("rux: variant name cannot be empty")
+	}
+	if weight < 0 || weight > 100 {
+		panic("rux: variant weight must be in 0..100")
+	}
+	ng := g.Group("")
+	ng.variant = &routeVariant{name: name, weight: weight}
+	if len(key) > 0 {
+		ng.variant.key = key[0]
+	}
+	return ng
+}
+
+// routeVariant is the variant of the routes of a Variant group.
+type routeVariant struct {
+	name   string
+	weight int
+	key    VariantKey
+}
+
+// Variant returns the variant name of a route of Router.Variant, or "".
+func (r *Route) Variant() string {
+	if r.variant == nil {
+		return ""
+	}
+	return r.variant.name
+}
+
+// Weight returns the variant weight of the route, 0 for the route getting
+// the remaining percentage.
+func (r *Route) Weight() int {
+	if r.variant == nil {
+		return 0
+	}
+	return r.variant.weight
+}
+
+// Variant returns the variant name of the matched route, see
+// Router.Variant. It is "" for routes registered without one.
+func (c *Context) Variant() string {
+	if c.matchedRoute == nil {
+		return ""
+	}
+	return c.matchedRoute.Variant()
+}
+
+// variantString describes the variant of r for route dumps.
+func (r *Route) variantString() string {
+	if r.variant == nil {
+		return ""
+	}
+	if r.variant.weight == 0 {
+		return r.variant.name + " (rest)"
+	}
+	return r.variant.name + " " + strconv.Itoa(r.variant.weight) + "%"
+}
+
+// checkWeights panics if adding route to run, routes sharing their
+// conditions, makes the weighted variants exceed 100 percent while a
+// route gets the rest.
+func checkWeights(run []*Route, route *Route) {
+	rest, total := route.Weight() == 0, route.Weight()
+	for _, v := range run {
+		if w := v.Weight(); w == 0 {
+			rest = true
+		} else {
+			total += w
+		}
+	}
+	if rest && total > 100 {
+		panic(fmt.Sprintf("rux: variant weights of %s %s add up to %d%%, more than 100%%",
+			route.MethodString(","), route.path, total))
+	}
+}
+
+// pickWeighted picks a route of run, routes sharing their conditions, by
+// variant weight.
+func pickWeighted(run []*Route, req *http.Request) *Route {
+	var rest *Route
+	var key VariantKey
+	total := 0
+	for _, v := range run {
+		if v.Weight() == 0 {
+			rest = v
+		} else {
+			total += v.variant.weight
+		}
+		if key == nil && v.variant != nil {
+			key = v.variant.key
+		}
+	}
+	if rest != nil {
+		total = 100
+	}
+
+	n := -1
+	if key != nil {
+		if k := key(req); k != "" {
+			n = int(fnv32a(k) % uint32(total))
+		}
+	}
+	if n < 0 {
+		n = rand.IntN(total)
+	}
+	for _, v := range run {
+		if w := v.Weight(); w > 0 {
+			if n < w {
+				return v
+			}
+			n -= w
+		}
+	}
+	return rest
+}
+
+// fnv32a returns the FNV-1a hash of s.
+func fnv32a(s string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
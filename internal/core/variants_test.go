@@ -0,0 +1,127 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func variantHandler(c *Context) { c.Text(200, c.Variant()) }
+
+func TestRouter_Variant_Weights(t *testing.T) {
+	r := New()
+	r.GET("/checkout", variantHandler)
+	r.Variant("canary", 20).GET("/checkout", variantHandler)
+	r.Variant("full", 100).GET("/all", variantHandler)
+	r.Variant("off", 0).GET("/all", variantHandler)
+
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		counts[serve(r, GET, "/checkout").Body.String()]++
+	}
+	assert.Len(t, counts, 2)
+	assert.True(t, counts["canary"] > 250 && counts["canary"] < 550, counts)
+	assert.Eq(t, 2000-counts["canary"], counts[""])
+
+	for i := 0; i < 20; i++ {
+		assert.Eq(t, "full", serve(r, GET, "/all").Body.String())
+	}
+}
+
+func TestRouter_Variant_Relative(t *testing.T) {
+	r := New()
+	r.Variant("a", 1).GET("/ab", variantHandler)
+	r.Variant("b", 1).GET("/ab", variantHandler)
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		counts[serve(r, GET, "/ab").Body.String()]++
+	}
+	assert.True(t, counts["a"] > 100 && counts["b"] > 100, counts)
+}
+
+func TestRouter_Variant_Sticky(t *testing.T) {
+	r := New()
+	r.GET("/home", variantHandler)
+	r.Variant("new", 50, ByCookie("uid")).GET("/home", variantHandler)
+	r.Variant("fast", 50, ByHeader("X-User")).GET("/users/{id}", variantHandler)
+	r.Variant("slow", 50).GET("/users/{id}", variantHandler)
+	r.Variant("ip", 50, ByClientIP).GET("/ip", variantHandler)
+	r.GET("/ip", variantHandler)
+
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		uid := strconv.Itoa(i)
+		first := serve(r, GET, "/home", "Cookie", "uid="+uid).Body.String()
+		seen[first] = true
+		for j := 0; j < 5; j++ {
+			assert.Eq(t, first, serve(r, GET, "/home", "Cookie", "uid="+uid).Body.String())
+		}
+
+		user := serve(r, GET, "/users/1", "X-User", uid).Body.String()
+		assert.Eq(t, user, serve(r, GET, "/users/2", "X-User", uid).Body.String())
+		ip := serve(r, GET, "/ip", "X-Forwarded-For", "10.0.0."+uid).Body.String()
+		assert.Eq(t, ip, serve(r, GET, "/ip", "X-Forwarded-For", "10.0.0."+uid).Body.String())
+	}
+	assert.Len(t, seen, 2)
+}
+
+func TestRouter_Variant_WithConditions(t *testing.T) {
+	r := New()
+	v2 := r.When(HeaderIs("Accept-Version", "2"))
+	v2.GET("/items", variantHandler)
+	v2.Variant("v2-canary", 100).GET("/items", variantHandler)
+	r.GET("/items", variantHandler)
+
+	assert.Eq(t, "v2-canary", serve(r, GET, "/items", "Accept-Version", "2").Body.String())
+	assert.Eq(t, "", serve(r, GET, "/items").Body.String())
+}
+
+func TestRouter_Variant_Fallback(t *testing.T) {
+	// The "/*" fallback has no registration API; store a slot of variants
+	// as routeTable.register would.
+	r := New(HandleFallbackRoute)
+	var slot *Route
+	for _, name := range []string{"stable", "canary"} {
+		v := newRoute("/*", textHandler(name), nil)
+		v.finalChain = v.chain
+		if name == "canary" {
+			v.variant = &routeVariant{name: name, weight: 100}
+		}
+		slot, _ = addVariant(slot, v)
+	}
+	r.staticRoutes[methodIndex(GET)] = map[string]*Route{"/*": slot}
+
+	assert.Eq(t, "canary", serve(r, GET, "/missing").Body.String())
+	ex := r.Explain(GET, "/missing")
+	assert.Eq(t, "fallback", ex.Outcome)
+	assert.Eq(t, "canary", ex.Variant)
+	assert.Len(t, ex.Handlers, 1)
+}
+
+func TestRouter_Variant_Registration(t *testing.T) {
+	r := New()
+	r.GET("/x", variantHandler)
+	r.Variant("a", 60).GET("/x", variantHandler)
+	assert.PanicsMsg(t, func() { r.Variant("b", 50).GET("/x", variantHandler) },
+		"rux: variant weights of GET /x add up to 110%, more than 100%")
+	assert.PanicsMsg(t, func() { r.Variant("c", 0).GET("/x", variantHandler) },
+		"rux: duplicate static route: GET /x")
+	assert.PanicsMsg(t, func() { r.Variant("", 1) }, "rux: variant name cannot be empty")
+	assert.PanicsMsg(t, func() { r.Variant("d", 101) }, "rux: variant weight must be in 0..100")
+
+	r = New()
+	r.GET("/x", variantHandler)
+	r.Variant("a", 60).GET("/x", variantHandler)
+	routes := r.Routes()
+	assert.Eq(t, "a", routes[1].Variant)
+	assert.Eq(t, 60, routes[1].Weight)
+	assert.True(t, strings.Contains(r.String(), "variant a 60%"))
+	assert.True(t, r.Analyze().OK())
+
+	ex := r.Explain(GET, "/x")
+	assert.Eq(t, "matched", ex.Outcome)
+	assert.Eq(t, ex.Route.Variant(), ex.Variant)
+}
@@ -0,0 +1,438 @@
+package core
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIVersions is a set of API versions served side by side below a path
+// prefix. Each version is a RouteGroup; a version serves its own routes
+// and, for the routes it does not register, the ones of the previous
+// versions. See Router.Versions.
+type APIVersions struct {
+	router *Router
+	// base is the group the versions are nested in.
+	base *RouteGroup
+
+	byPath bool
+	header string
+	vendor string
+
+	versions []*apiVersion
+	// def is the version of requests that do not select one, and of
+	// route names without a version. The latest version if empty.
+	def string
+}
+
+// apiVersion is a version of an APIVersions.
+type apiVersion struct {
+	name string
+	// routes are the routes registered in the version, added to the
+	// router on Freeze.
+	routes     []versionRoute
+	deprecated HandlerFunc
+}
+
+// versionRoute is a route registered in a version group.
+type versionRoute struct {
+	route *Route
+	// group is the group the route was registered in, scoped the
+	// UseFromNow middlewares in effect then.
+	group  *RouteGroup
+	scoped HandlersChain
+}
+
+// VersionOption selects how requests choose an API version, see
+// Router.Versions.
+type VersionOption func(*APIVersions)
+
+// VersionByPath serves version routes below a path segment named after
+// the version: /api/v2/users. It is the default when no VersionOption is
+// given.
+func VersionByPath(vs *APIVersions) { vs.byPath = true }
+
+// VersionByHeader selects the version by the name header, e.g.
+// "Api-Version: v2" or "Api-Version: 2", on the unversioned path.
+func VersionByHeader(name string) VersionOption {
+	return func(vs *APIVersions) { vs.header = http.CanonicalHeaderKey(name) }
+}
+
+// VersionByAccept selects the version by a vendor media type of the Accept
+// header, e.g. "application/vnd.acme.v2+json" for vendor "acme", on the
+// unversioned path.
+func VersionByAccept(vendor string) VersionOption {
+	return func(vs *APIVersions) { vs.vendor = strings.ToLower(vendor) }
+}
+
+// Versions returns an API version set below prefix. See RouteGroup.Versions.
+func (r *Router) Versions(prefix string, opts ...VersionOption) *APIVersions {
+	return r.group().Versions(prefix, opts...)
+}
+
+// Versions returns an API version set below prefix, nested in g. opts
+// select how a request chooses a version, VersionByPath by default.
+// Several options can be combined: with VersionByPath and a header or
+// media type option, routes are served on both paths.
+//
+//	api := r.Versions("/api", rux.VersionByPath, rux.VersionByHeader("Api-Version"))
+//	v1 := api.Version("v1")
+//	v1.GET("/users", listUsers)
+//	v1.GET("/users/{id}", showUser)
+//	v2 := api.Version("v2")
+//	v2.GET("/users", listUsersV2)
+//	// GET /api/v2/users/7 -> showUser
+//	api.Deprecate("v1", rux.Deprecation{Sunset: sunset})
+//
+// Version routes are added to the router on Freeze: a version serves its
+// own routes and, per method and path, the latest route of the previous
+// versions. Inherited routes keep the middlewares of the version group
+// they were registered in. Before Freeze, GetRoute, BuildURL, NamedRoutes
+// and Routes already report them.
+//
+// Without a version in the path, a request selects the version by header,
+// then by Accept media type, else gets the default version (see Default).
+// A request for a version that does not serve the path gets 406.
+//
+// Named routes are named "name@version" for each version serving them;
+// the name alone is the route of the default version, so BuildURL
+// builds the versioned URL. A route without a name overriding a named
+// route of a previous version takes over its name, unless the version
+// gives the name to another route.
+func (g *RouteGroup) Versions(prefix string, opts ...VersionOption) *APIVersions {
+	vs := &APIVersions{router: g.router, base: g.Group(prefix)}
+	for _, opt := range opts {
+		opt(vs)
+	}
+	if vs.header == "" && vs.vendor == "" {
+		vs.byPath = true
+	}
+
+	r := g.router
+	r.mu.Lock()
+	r.versionSets = append(r.versionSets, vs)
+	r.mu.Unlock()
+	return vs
+}
+
+// Version returns the group of the version name, declaring it after the
+// existing versions on first use. middles run before the middlewares of
+// the version's routes.
+func (vs *APIVersions) Version(name string, middles ...HandlerFunc) *RouteGroup {
+	if name == "" || strings.ContainsAny(name, "/@") {
+		panic("rux: invalid API version name " + strconv.Quote(name))
+	}
+	r := vs.router
+	r.lockUnfrozen("rux: cannot add an API version after router is frozen")
+	v := vs.lookup(name)
+	if v == nil {
+		v = &apiVersion{name: name}
+		vs.versions = append(vs.versions, v)
+	}
+	r.mu.Unlock()
+
+	g := vs.base.Group("", middles...)
+	g.version = v
+	return g
+}
+
+// Default sets the version of requests that select none, and of route
+// names without a version. The latest version by default.
+func (vs *APIVersions) Default(name string) *APIVersions {
+	if vs.lookup(name) == nil {
+		panic("rux: unknown API version " + strconv.Quote(name))
+	}
+	vs.def = name
+	return vs
+}
+
+// Names returns the versions in declaration order.
+func (vs *APIVersions) Names() []string {
+	names := make([]string, len(vs.versions))
+	for i, v := range vs.versions {
+		names[i] = v.name
+	}
+	return names
+}
+
+// Deprecation describes a deprecated API version, see APIVersions.Deprecate.
+type Deprecation struct {
+	// Since is when the version was deprecated, sent as the Deprecation
+	// header (RFC 9745). Zero sends "Deprecation: true".
+	Since time.Time
+	// Sunset is when the version stops being served, sent as the Sunset
+	// header (RFC 8594) if set.
+	Sunset time.Time
+	// Link is the URL of the deprecation notice, sent as a Link header
+	// with rel="deprecation" if set.
+	Link string
+}
+
+// Deprecate marks the version name deprecated: its responses get the
+// Deprecation, Sunset and Link headers of d, inherited routes included.
+func (vs *APIVersions) Deprecate(name string, d Deprecation) *APIVersions {
+	v := vs.lookup(name)
+	if v == nil {
+		panic("rux: unknown API version " + strconv.Quote(name))
+	}
+	if vs.router.frozen.Load() {
+		panic("rux: cannot deprecate an API version after router is frozen")
+	}
+	v.deprecated = d.handler()
+	return vs
+}
+
+// handler returns the middleware setting the headers of d.
+func (d Deprecation) handler() HandlerFunc {
+	dep := "true"
+	if !d.Since.IsZero() {
+		dep = "@" + strconv.FormatInt(d.Since.Unix(), 10)
+	}
+	var sunset string
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	return func(c *Context) {
+		h := c.Resp.Header()
+		h.Set("Deprecation", dep)
+		if sunset != "" {
+			h.Set("Sunset", sunset)
+		}
+		if d.Link != "" {
+			h.Add("Link", "<"+d.Link+`>; rel="deprecation"`)
+		}
+	}
+}
+
+// BuildURL builds the URL of the named route in version. See
+// Router.BuildURL.
+func (vs *APIVersions) BuildURL(version, name string, buildArgs ...any) *url.URL {
+	return vs.router.BuildURL(name+"@"+version, buildArgs...)
+}
+
+// Version returns the API version of a route registered in a version
+// group, or "".
+func (r *Route) Version() string { return r.version }
+
+func (vs *APIVersions) lookup(name string) *apiVersion {
+	for _, v := range vs.versions {
+		if v.name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// defaultVersion returns the version of requests that select none.
+func (vs *APIVersions) defaultVersion() string {
+	if vs.def != "" {
+		return vs.def
+	}
+	if len(vs.versions) == 0 {
+		return ""
+	}
+	return vs.versions[len(vs.versions)-1].name
+}
+
+// requested returns the version req selects by header or Accept media
+// type, or "".
+func (vs *APIVersions) requested(req *http.Request) string {
+	if vs.header != "" {
+		if v := strings.TrimSpace(req.Header.Get(vs.header)); v != "" {
+			return vs.normalize(v)
+		}
+	}
+	if vs.vendor != "" {
+		prefix := "application/vnd." + vs.vendor + "."
+		for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+			mt, _, _ := strings.Cut(accept, ";")
+			mt = strings.ToLower(strings.TrimSpace(mt))
+			if v, ok := strings.CutPrefix(mt, prefix); ok {
+				v, _, _ = strings.Cut(v, "+")
+				return vs.normalize(v)
+			}
+		}
+	}
+	return ""
+}
+
+// normalize maps "2" to a version named "v2".
+func (vs *APIVersions) normalize(v string) string {
+	if vs.lookup(v) == nil && vs.lookup("v"+v) != nil {
+		return "v" + v
+	}
+	return v
+}
+
+// condition returns the route condition of version name.
+func (vs *APIVersions) condition(name string) Condition {
+	return Condition{
+		desc:   "version " + name,
+		status: http.StatusNotAcceptable,
+		fn: func(req *http.Request) bool {
+			v := vs.requested(req)
+			if v == "" {
+				v = vs.defaultVersion()
+			}
+			return v == name
+		},
+	}
+}
+
+// add records route, registered in the version group g. Callers hold
+// router.mu.
+func (v *apiVersion) add(route *Route, g *RouteGroup) {
+	v.routes = append(v.routes, versionRoute{route: route, group: g, scoped: g.router.scopedChain})
+}
+
+// register adds the routes of every version to the router, on Freeze.
+// Callers hold router.mu.
+func (vs *APIVersions) register() {
+	r := vs.router
+	vs.build(func(route *Route, g *RouteGroup, scoped HandlersChain, isDefault bool) {
+		r.addRoute(route, g, scoped)
+		if isDefault && route.name != "" {
+			r.namedRoutes[strings.TrimSuffix(route.name, "@"+route.version)] = route
+		}
+	})
+}
+
+// pending returns the routes register adds, with their group applied but
+// not stored, and records them in names as register does. Callers hold
+// router.mu.
+func (vs *APIVersions) pending(names map[string]*Route) []*Route {
+	r := vs.router
+	var routes []*Route
+	vs.build(func(route *Route, g *RouteGroup, scoped HandlersChain, isDefault bool) {
+		r.applyGroup(route, g, scoped)
+		route.originalPath = route.path
+		route.constraints = parseConstraints(route.path)
+		if !hasOptionalSegment(route.path) {
+			route.path = r.routePath(convertParamSyntax(route.path))
+		}
+		if route.name != "" {
+			names[route.name] = route
+			if isDefault {
+				names[strings.TrimSuffix(route.name, "@"+route.version)] = route
+			}
+		}
+		routes = append(routes, route)
+	})
+	return routes
+}
+
+// build calls add with a copy of each route the versions serve, the group
+// it is added in and the UseFromNow middlewares in effect when it was
+// registered. isDefault is set for the routes of the default version.
+func (vs *APIVersions) build(add func(route *Route, g *RouteGroup, scoped HandlersChain, isDefault bool)) {
+	def := vs.defaultVersion()
+	for i, v := range vs.versions {
+		names := vs.inheritedNames(i)
+		served := make(map[string]bool)
+		for j := i; j >= 0; j-- {
+			for _, vr := range vs.versions[j].routes {
+				var methods []string
+				name := vr.route.name
+				for _, m := range vr.route.methods {
+					key := vs.routeKey(m, vr)
+					if !served[key] {
+						served[key] = true
+						methods = append(methods, m)
+						if name == "" {
+							name = names[key]
+						}
+					}
+				}
+				if len(methods) > 0 {
+					vs.buildRoute(v, vr, methods, name, v.name == def, add)
+				}
+			}
+		}
+	}
+}
+
+// inheritedNames returns the names of the routes of the versions before
+// the one at index i, by routeKey, the latest first. Names the version
+// gives its own routes are left out, so an unnamed route overriding a
+// named one takes over its name.
+func (vs *APIVersions) inheritedNames(i int) map[string]string {
+	names := make(map[string]string)
+	for _, v := range vs.versions[:i] {
+		for _, vr := range v.routes {
+			if vr.route.name == "" {
+				continue
+			}
+			for _, m := range vr.route.methods {
+				names[vs.routeKey(m, vr)] = vr.route.name
+			}
+		}
+	}
+	for _, vr := range vs.versions[i].routes {
+		if vr.route.name == "" {
+			continue
+		}
+		for key, name := range names {
+			if name == vr.route.name {
+				delete(names, key)
+			}
+		}
+	}
+	return names
+}
+
+// routeKey identifies the method m of the route of vr across versions.
+func (vs *APIVersions) routeKey(m string, vr versionRoute) string {
+	return m + " " + vs.relPath(vr) + " " + strings.Join(condStrings(vr.group.conds), ", ")
+}
+
+// relPath returns the path of vr below the version set prefix, in table
+// form.
+func (vs *APIVersions) relPath(vr versionRoute) string {
+	r := vs.router
+	rel := strings.TrimPrefix(vr.group.prefix, vs.base.prefix) + r.formatPath(vr.route.path)
+	return r.formatPath(convertParamSyntax(rel))
+}
+
+// buildRoute calls add with a copy of the route of vr for version v,
+// serving methods and named name, for each path of the version set.
+func (vs *APIVersions) buildRoute(v *apiVersion, vr versionRoute, methods []string, name string, isDefault bool,
+	add func(route *Route, g *RouteGroup, scoped HandlersChain, isDefault bool)) {
+	addCopy := func(seg string, conds []Condition, name string) {
+		route := *vr.route
+		route.methods = methods
+		route.version = v.name
+		route.name = ""
+		if name != "" {
+			route.name = name + "@" + v.name
+		}
+		add(&route, vs.servingGroup(v, vr.group, seg, conds), vr.scoped, isDefault)
+	}
+	if vs.byPath {
+		addCopy("/"+v.name, vr.group.conds, name)
+		// Named on the versioned path only.
+		name = ""
+	}
+	if vs.header != "" || vs.vendor != "" {
+		addCopy("", append(append([]Condition{}, vr.group.conds...), vs.condition(v.name)), name)
+	}
+}
+
+// servingGroup returns the group a route of decl is added in for version
+// v: decl's prefix with the version segment seg, its middlewares and
+// hooks, and the deprecation middleware of v.
+func (vs *APIVersions) servingGroup(v *apiVersion, decl *RouteGroup, seg string, conds []Condition) *RouteGroup {
+	g := &RouteGroup{
+		router:     vs.router,
+		parent:     decl,
+		prefix:     vs.base.prefix + seg + strings.TrimPrefix(decl.prefix, vs.base.prefix),
+		host:       decl.host,
+		namePrefix: decl.namePrefix,
+		conds:      conds,
+	}
+	if v.deprecated != nil {
+		g.handlers = HandlersChain{v.deprecated}
+	}
+	return g
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestRouter_Versions_ByPath(t *testing.T) {
+	r := New()
+	api := r.Versions("/api")
+	v1 := api.Version("v1", textHandler("v1 mw:"))
+	v1.GET("/users", textHandler("list v1"))
+	v1.GET("/users/{id}", func(c *Context) { c.Text(200, "show "+c.Param("id")+" "+c.Route().Version()) })
+	v1.POST("/users", textHandler("create v1"))
+	v2 := api.Version("v2")
+	v2.GET("/users", textHandler("list v2"))
+	v2.Group("/admin").GET("/stats", textHandler("stats v2"))
+
+	assert.Eq(t, []string{"v1", "v2"}, api.Names())
+	assert.Eq(t, "v1 mw:list v1", serve(r, GET, "/api/v1/users").Body.String())
+	assert.Eq(t, "list v2", serve(r, GET, "/api/v2/users").Body.String())
+	// Unchanged routes fall back to the previous version.
+	assert.Eq(t, "v1 mw:show 7 v2", serve(r, GET, "/api/v2/users/7").Body.String())
+	assert.Eq(t, "v1 mw:create v1", serve(r, POST, "/api/v2/users").Body.String())
+	assert.Eq(t, "stats v2", serve(r, GET, "/api/v2/admin/stats").Body.String())
+	assert.Eq(t, 404, serve(r, GET, "/api/v1/admin/stats").Code)
+	assert.Eq(t, 404, serve(r, GET, "/api/users").Code)
+}
+
+func TestRouter_Versions_ByHeaderAndAccept(t *testing.T) {
+	r := New()
+	api := r.Versions("/api", VersionByHeader("Api-Version"), VersionByAccept("acme"))
+	api.Version("v1").GET("/users", textHandler("v1"))
+	api.Version("v1").GET("/users/{id}", textHandler("show v1"))
+	api.Version("v2").GET("/users", textHandler("v2"))
+	api.Default("v1")
+
+	assert.Eq(t, "v1", serve(r, GET, "/api/users").Body.String())
+	assert.Eq(t, "v2", serve(r, GET, "/api/users", "Api-Version", "2").Body.String())
+	assert.Eq(t, "v2", serve(r, GET, "/api/users", "Api-Version", "v2").Body.String())
+	assert.Eq(t, "v2", serve(r, GET, "/api/users", "Accept", "text/html, application/vnd.acme.v2+json").Body.String())
+	assert.Eq(t, "show v1", serve(r, GET, "/api/users/1", "Api-Version", "2").Body.String())
+	assert.Eq(t, http.StatusNotAcceptable, serve(r, GET, "/api/users", "Api-Version", "3").Code)
+	assert.Eq(t, 404, serve(r, GET, "/api/v2/users").Code)
+}
+
+func TestRouter_Versions_Deprecate(t *testing.T) {
+	r := New()
+	api := r.Versions("/api", VersionByPath, VersionByHeader("Api-Version"))
+	api.Version("v1").GET("/users", textHandler("v1"))
+	api.Version("v1").GET("/ping", textHandler("pong"))
+	api.Version("v2").GET("/users", textHandler("v2"))
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	api.Deprecate("v1", Deprecation{
+		Since:  time.Unix(1700000000, 0),
+		Sunset: sunset,
+		Link:   "https://example.com/deprecation",
+	})
+
+	w := serve(r, GET, "/api/v1/users")
+	assert.Eq(t, "v1", w.Body.String())
+	assert.Eq(t, "@1700000000", w.Header().Get("Deprecation"))
+	assert.Eq(t, "Fri, 01 Jan 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Eq(t, `<https://example.com/deprecation>; rel="deprecation"`, w.Header().Get("Link"))
+
+	w = serve(r, GET, "/api/users", "Api-Version", "v1")
+	assert.Eq(t, "@1700000000", w.Header().Get("Deprecation"))
+
+	// Routes inherited by v2 are not deprecated.
+	w = serve(r, GET, "/api/v2/ping")
+	assert.Eq(t, "pong", w.Body.String())
+	assert.Eq(t, "", w.Header().Get("Deprecation"))
+	assert.Eq(t, "", serve(r, GET, "/api/v2/users").Header().Get("Deprecation"))
+
+	r = New()
+	api = r.Versions("/api")
+	api.Version("v1").GET("/users", textHandler("v1"))
+	api.Deprecate("v1", Deprecation{})
+	w = serve(r, GET, "/api/v1/users")
+	assert.Eq(t, "true", w.Header().Get("Deprecation"))
+	assert.Eq(t, "", w.Header().Get("Sunset"))
+}
+
+func TestRouter_Versions_BuildURL(t *testing.T) {
+	r := New()
+	api := r.Versions("/api")
+	api.Version("v1").Name("api.").AddNamed("user", "/users/{id}", textHandler("v1"))
+	api.Version("v2").GET("/ping", textHandler("pong"))
+	r.Freeze()
+
+	assert.Eq(t, "/api/v1/users/7", r.BuildURL("api.user@v1", "{id}", 7).String())
+	assert.Eq(t, "/api/v2/users/7", r.BuildURL("api.user@v2", "{id}", 7).String())
+	assert.Eq(t, "/api/v2/users/7", r.BuildURL("api.user", "{id}", 7).String())
+	assert.Eq(t, "/api/v1/users/7", api.BuildURL("v1", "api.user", "{id}", 7).String())
+	assert.Eq(t, "v2", r.GetRoute("api.user").Version())
+
+	var versions []string
+	for _, ri := range r.Routes() {
+		if strings.HasPrefix(ri.Name, "api.user") {
+			versions = append(versions, ri.Version)
+		}
+	}
+	assert.Eq(t, []string{"v1", "v2"}, versions)
+}
+
+// Version routes and names are reported before Freeze, as after.
+func TestRouter_Versions_BeforeFreeze(t *testing.T) {
+	r := New()
+	api := r.Versions("/api")
+	api.Version("v1").AddNamed("users", "/users/{id}", textHandler("v1"))
+	api.Version("v1").AddNamed("ping", "/ping", textHandler("pong"))
+	// Overrides users without a name: it takes over the name.
+	api.Version("v2").GET("/users/{id}", textHandler("v2"))
+
+	assert.Eq(t, "/api/v2/users/7", r.BuildURL("users", "{id}", 7).String())
+	assert.Eq(t, "/api/v1/users/7", r.BuildURL("users@v1", "{id}", 7).String())
+	assert.Eq(t, "/api/v2/ping", r.BuildURL("ping").String())
+	assert.NotNil(t, r.NamedRoutes()["ping@v1"])
+	before := r.Routes()
+	assert.Len(t, before, 4)
+
+	r.Freeze()
+	assert.Eq(t, before, r.Routes())
+	assert.Eq(t, "/api/v2/users/7", r.BuildURL("users", "{id}", 7).String())
+	assert.Eq(t, "v2", r.GetRoute("users").Version())
+	assert.Eq(t, "v2", serve(r, GET, "/api/v2/users/7").Body.String())
+
+	// The name stays with its route when the version gives it to another.
+	r = New()
+	api = r.Versions("/api")
+	api.Version("v1").AddNamed("users", "/users", textHandler("v1"))
+	api.Version("v2").GET("/users", textHandler("v2"))
+	api.Version("v2").AddNamed("users", "/people", textHandler("people"))
+	assert.Eq(t, "/api/v2/people", r.BuildURL("users").String())
+}
+
+func TestRouter_Versions_Panics(t *testing.T) {
+	r := New()
+	api := r.Versions("/api")
+	assert.PanicsMsg(t, func() { api.Version("v/1") }, `rux: invalid API version name "v/1"`)
+	assert.PanicsMsg(t, func() { api.Default("v9") }, `rux: unknown API version "v9"`)
+	assert.PanicsMsg(t, func() { api.Deprecate("v9", Deprecation{}) }, `rux: unknown API version "v9"`)
+}
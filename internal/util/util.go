@@ -16,8 +16,10 @@ func Panicf(format string, a ...any) {
 // 1. 可选参数只能在路径最后
 // 2. 只能支持一个可选参数
 func ValidateOptionalSegments(path string) {
-	firstOptionalPos := strings.IndexByte(path, '[')
-	lastOptionalPos := strings.LastIndexByte(path, '[')
+	// 忽略参数正则中的方括号，如 {id:[0-9]+}
+	masked := maskBraces(path)
+	firstOptionalPos := strings.IndexByte(masked, '[')
+	lastOptionalPos := strings.LastIndexByte(masked, '[')
 
 	// 没有可选参数，直接返回
 	if firstOptionalPos == -1 {
@@ -30,10 +32,32 @@ func ValidateOptionalSegments(path string) {
 	}
 
 	// 规则 2：可选参数后不能有其他路径段
-	closingBracketPos := strings.IndexByte(path, ']')
+	closingBracketPos := strings.IndexByte(masked, ']')
 	afterOptionalPos := closingBracketPos + 1
 	if afterOptionalPos < len(path) {
 		Panicf("route %s: optional segment must be at the end of the path, found '%s' after ']'",
 			path, path[afterOptionalPos:])
 	}
 }
+
+// maskBraces 将花括号内的内容替换为 '_'，保持字符位置不变
+func maskBraces(path string) string {
+	if strings.IndexByte(path, '{') == -1 {
+		return path
+	}
+	bs := []byte(path)
+	depth := 0
+	for i, c := range bs {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		default:
+			if depth > 0 {
+				bs[i] = '_'
+			}
+		}
+	}
+	return string(bs)
+}
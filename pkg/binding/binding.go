@@ -2,6 +2,8 @@
 package binding
 
 import (
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"net/http"
 	"strings"
@@ -10,6 +12,9 @@ import (
 // DefaultMaxMemory for parse data form
 var DefaultMaxMemory int64 = 32 << 20 // 32 MB
 
+// PathTagName for binding route path params. eg: `path:"id"`
+var PathTagName = "path"
+
 // MustBind auto bind request data to an struct ptr
 func MustBind(r *http.Request, obj any) {
 	err := Auto(r, obj)
@@ -46,7 +51,7 @@ func Auto(r *http.Request, obj any) (err error) {
 	// basic POST form data binding. content type: "application/x-www-form-urlencoded"
 	if strings.Contains(cType, "/x-www-form-urlencoded") {
 		if err = r.ParseForm(); err != nil {
-			return err
+			return bindError(err)
 		}
 
 		return Form.BindValues(r.PostForm, obj)
@@ -57,7 +62,7 @@ func Auto(r *http.Request, obj any) (err error) {
 	if strings.Contains(cType, "/form-data") {
 		err = r.ParseMultipartForm(DefaultMaxMemory)
 		if err != nil {
-			return err
+			return bindError(err)
 		}
 
 		return Form.BindValues(r.PostForm, obj)
@@ -73,5 +78,28 @@ func Auto(r *http.Request, obj any) (err error) {
 		return XML.Bind(r, obj)
 	}
 
-	return errors.New("cannot auto binding request data, content-type: " + cType)
+	return bindError(errors.New("cannot auto binding request data, content-type: " + cType))
+}
+
+// DecodeBody decode the request body to a ptr value by content type,
+// without validation. Supports form, multipart form, JSON and XML bodies.
+func DecodeBody(r *http.Request, obj any) (err error) {
+	cType := r.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(cType, "/x-www-form-urlencoded"):
+		if err = r.ParseForm(); err != nil {
+			return bindError(err)
+		}
+		return DecodeValues(r.PostForm, obj, FormTagName)
+	case strings.Contains(cType, "/form-data"):
+		if err = r.ParseMultipartForm(DefaultMaxMemory); err != nil {
+			return bindError(err)
+		}
+		return DecodeValues(r.PostForm, obj, FormTagName)
+	case strings.Contains(cType, "/json"):
+		return bindError(json.NewDecoder(r.Body).Decode(obj))
+	case strings.Contains(cType, "/xml"):
+		return bindError(xml.NewDecoder(r.Body).Decode(obj))
+	}
+	return bindError(errors.New("cannot decode request body, content-type: " + cType))
 }
@@ -251,6 +251,38 @@ func TestBinder_DecodeChain_HitsValidator(t *testing.T) {
 	assert.True(t, strings.Contains(err.Error(), "nope"))
 }
 
+func TestDecodeValues_SkipsValidationAndUnknownKeys(t *testing.T) {
+	m := &mockValidator{err: errors.New("nope")}
+	withMockValidator(t, m)
+
+	u := &User{}
+	err := binding.DecodeValues(map[string][]string{"age": {"3"}, "other": {"x"}}, u, "query")
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, u.Age)
+	assert.False(t, m.called)
+}
+
+func TestDecodeBody(t *testing.T) {
+	m := &mockValidator{err: errors.New("nope")}
+	withMockValidator(t, m)
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("age=5&name=tom"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	u := &User{}
+	assert.NoErr(t, binding.DecodeBody(req, u))
+	assert.Eq(t, "tom", u.Name)
+	assert.False(t, m.called)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`<User><age>7</age></User>`))
+	req.Header.Set("Content-Type", "text/xml")
+	assert.NoErr(t, binding.DecodeBody(req, u))
+	assert.Eq(t, 7, u.Age)
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("x"))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	assert.Err(t, binding.DecodeBody(req, u))
+}
+
 // ----- helpers ----------------------------------------------------
 
 // buildMultipart serializes the given fields as multipart/form-data and
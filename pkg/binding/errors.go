@@ -0,0 +1,31 @@
+package binding
+
+// BindError is returned when request data cannot be parsed or decoded.
+type BindError struct {
+	Err error
+}
+
+// Error implements error.
+func (e *BindError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the decode error.
+func (e *BindError) Unwrap() error { return e.Err }
+
+// ValidationError is returned when bound data fails Validator.
+type ValidationError struct {
+	Err error
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the validator error.
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// bindError wraps a non-nil err as *BindError.
+func bindError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &BindError{Err: err}
+}
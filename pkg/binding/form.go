@@ -24,7 +24,7 @@ func (FormBinder) Name() string {
 func (b FormBinder) Bind(r *http.Request, ptr any) error {
 	err := r.ParseForm()
 	if err != nil {
-		return err
+		return bindError(err)
 	}
 
 	return DecodeUrlValues(r.Form, ptr, b.TagName)
@@ -42,7 +42,18 @@ func DecodeUrlValues(values map[string][]string, ptr any, tagName string) error
 	})
 
 	if err := dec.Decode(values, ptr); err != nil {
-		return err
+		return bindError(err)
 	}
 	return Validate(ptr)
 }
+
+// DecodeValues decode url.Values data to struct, without validation.
+// Unknown keys are ignored, so a struct can be filled from several
+// sources (query, headers, path params) before validating.
+func DecodeValues(values map[string][]string, ptr any, tagName string) error {
+	dec := formam.NewDecoder(&formam.DecoderOptions{
+		TagName:           tagName,
+		IgnoreUnknownKeys: true,
+	})
+	return bindError(dec.Decode(values, ptr))
+}
@@ -28,7 +28,7 @@ func (JSONBinder) BindBytes(bts []byte, ptr any) error {
 func decodeJSON(r io.Reader, ptr any) error {
 	err := json.NewDecoder(r).Decode(ptr)
 	if err != nil {
-		return err
+		return bindError(err)
 	}
 
 	return Validate(ptr)
@@ -14,11 +14,14 @@ func ResetValidator() {
 	Validator = nil
 }
 
-// Validate bounded data
+// Validate bounded data. Validator errors are returned as *ValidationError.
 func Validate(obj any) error {
 	// if Validator is nil, dont validate.
 	if Validator == nil {
 		return nil
 	}
-	return Validator.Validate(obj)
+	if err := Validator.Validate(obj); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
 }
@@ -29,7 +29,7 @@ func (XMLBinder) BindBytes(bts []byte, ptr any) error {
 func decodeXML(r io.Reader, obj any) error {
 	err := xml.NewDecoder(r).Decode(obj)
 	if err != nil {
-		return err
+		return bindError(err)
 	}
 
 	return Validate(obj)
@@ -50,18 +50,25 @@ func ConsoleLogger(ignorePaths ...string) rux.HandlerFunc {
 		// 	postData = string(buf)
 		// }
 
+		// the route variant of a weighted rollout, see rux.Router.Variant
+		variant := ""
+		if v := c.Variant(); v != "" {
+			variant = " (" + v + ")"
+		}
+
 		mColor := colorForMethod(c.Req.Method)
 		codeColor := colorForStatus(c.StatusCode())
 
 		ccolor.Printf(
 			// 2006/01/02T15:04:05 [rux] GET /articles 200 10.0.0.1 "use-agent" 0.034ms
 			// `%s %s %s %d %s "%s" %sms` + "\n",
-			"%s [%s] %s [%s] %s %sms\n",
+			"%s [%s] %s [%s] %s%s %sms\n",
 			start.Format("2006/01/02T15:04:05.000"),
 			c.ClientIP(),
 			mColor.Render(c.Req.Method),
 			codeColor.Render(c.StatusCode()),
 			c.Req.RequestURI,
+			variant,
 			// c.Header("User-Agent"),
 			calcElapsedTime(start),
 		)
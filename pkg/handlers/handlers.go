@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/gookit/rux/v2"
@@ -15,6 +16,32 @@ func DumpRoutesHandler() rux.HandlerFunc {
 	}
 }
 
+// ExplainHandler serves Router.ExplainRequest as JSON, for debugging
+// unexpected 404 and 405 responses. The request to explain is given by
+// the query: method (default GET), path and host (default the request
+// host). Register it on an admin or debug route:
+//
+//	r.GET("/debug/explain", handlers.ExplainHandler())
+//	// GET /debug/explain?method=POST&path=/users/1
+func ExplainHandler() rux.HandlerFunc {
+	return func(c *rux.Context) {
+		method := strings.ToUpper(c.Query("method", rux.GET))
+		path := c.Query("path")
+		if path == "" {
+			c.AbortWithStatus(http.StatusBadRequest, "query param path is required")
+			return
+		}
+
+		u, err := url.Parse(path)
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest, "invalid path: "+err.Error())
+			return
+		}
+		req := &http.Request{Method: method, URL: u, Host: c.Query("host", c.Req.Host)}
+		c.JSON(http.StatusOK, c.Router().ExplainRequest(req))
+	}
+}
+
 /*************************************************************
  * for support method override
  *************************************************************/
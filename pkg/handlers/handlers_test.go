@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"strings"
 	"testing"
@@ -35,6 +36,22 @@ func TestDumpRoutesHandler(t *testing.T) {
 	art.Contains(w.Body.String(), "Routes Count: 1")
 }
 
+func TestExplainHandler(t *testing.T) {
+	r := rux.New(rux.HandleMethodNotAllowed)
+	r.GET("/debug/explain", ExplainHandler())
+	r.PUT("/users/{id:\\d+}", func(c *rux.Context) {})
+
+	w := testutil.MockRequest(r, "GET", "/debug/explain?method=get&path=/users/1", nil)
+	assert.Eq(t, 200, w.Code)
+	var ex rux.Explanation
+	assert.NoErr(t, json.Unmarshal(w.Body.Bytes(), &ex))
+	assert.Eq(t, "method not allowed", ex.Outcome)
+	assert.Eq(t, []string{"PUT"}, ex.Allowed)
+
+	w = testutil.MockRequest(r, "GET", "/debug/explain", nil)
+	assert.Eq(t, 400, w.Code)
+}
+
 func TestHTTPMethodOverrideHandler(t *testing.T) {
 	art := assert.New(t)
 
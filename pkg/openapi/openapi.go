@@ -0,0 +1,430 @@
+// Package openapi generates an OpenAPI 3.1 document from the routes
+// registered on a rux.Router.
+//
+// Paths and path parameters (including regex constraints such as
+// {id:\d+}) come from the routes themselves. Summaries, tags and the Go
+// types of the request and responses are attached per route with
+// Describe, or as route metadata (Route.With); tags are inherited from
+// group metadata:
+//
+//	users := r.NewGroup("/users").With("tags", []string{"users"})
+//	users.GET("/{id:\\d+}", showUser).With("summary", "Show a user")
+//
+//	openapi.Describe(r.POST("/users", createUser), openapi.RouteDoc{
+//	    Summary:   "Create a user",
+//	    Request:   CreateUserReq{},
+//	    Responses: map[int]any{201: User{}, 422: ErrorBody{}},
+//	})
+//
+// For routes registered with rux.AddTyped the request and response types
+// are known and used unless set explicitly.
+//
+// Request and response types are reflected into JSON Schema. Request
+// struct fields tagged query:"..." or header:"..." (the pkg/binding tags)
+// become query and header parameters; the other fields form the JSON
+// request body.
+//
+// The output is deterministic: map keys are sorted on encoding and
+// operations follow the route paths, so the document can be checked into
+// git and diffed.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/rux/v2"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.1.0"
+
+// Route metadata keys read by the generator.
+const (
+	// OptsKey holds a RouteDoc or *RouteDoc, as set by Describe.
+	OptsKey = "openapi"
+	// OptSummary, OptDescription and OptTags are shortcuts for the
+	// RouteDoc fields of the same name.
+	OptSummary     = "summary"
+	OptDescription = "description"
+	OptTags        = "tags"
+)
+
+// RouteDoc is the OpenAPI metadata of a route.
+type RouteDoc struct {
+	Summary     string
+	Description string
+	// OperationID defaults to the route name.
+	OperationID string
+	Tags        []string
+	Deprecated  bool
+	// Hidden leaves the route out of the document.
+	Hidden bool
+
+	// Request is a value (or nil pointer) of the request type.
+	Request any
+	// Responses maps status codes to a value of the response body type;
+	// nil means no body. Defaults to an empty 200 response.
+	Responses map[int]any
+}
+
+// Describe attaches doc to route and returns the route.
+func Describe(route *rux.Route, doc RouteDoc) *rux.Route {
+	if route.Opts == nil {
+		route.Opts = make(map[string]any, 1)
+	}
+	route.Opts[OptsKey] = &doc
+	return route
+}
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+}
+
+// Info is the document metadata.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL the API is served from.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of one path.
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+	Trace   *Operation `json:"trace,omitempty"`
+}
+
+// Operation describes one method on a path.
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes the request payload.
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response describes one response status.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas referenced by $ref.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// MarshalIndent encodes the document as indented JSON.
+func (d *Document) MarshalIndent() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Generate builds the document for the routes of r, including the routes
+// of mounted sub-routers. Host patterns are not represented; routes that
+// only differ by host share one path item.
+func Generate(r *rux.Router, info Info) *Document {
+	g := &generator{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]*PathItem),
+		},
+		schemas: newSchemaRegistry(),
+	}
+	g.walk(r, "")
+	if len(g.schemas.defs) > 0 {
+		g.doc.Components = &Components{Schemas: g.schemas.defs}
+	}
+	return g.doc
+}
+
+// generator accumulates the document while walking routes.
+type generator struct {
+	doc     *Document
+	schemas *schemaRegistry
+}
+
+func (g *generator) walk(r *rux.Router, prefix string) {
+	r.IterateRoutes(func(route *rux.Route) {
+		if sub := route.Mounted(); sub != nil {
+			g.walk(sub, prefix+strings.TrimRight(route.MountPrefix(), "/"))
+			return
+		}
+		if route.MountPrefix() != "" {
+			return // opaque http.Handler mount
+		}
+
+		doc := routeDoc(route)
+		if doc.Hidden {
+			return
+		}
+		path := route.Path()
+		if prefix != "" && path == "/" {
+			path = ""
+		}
+		for _, path := range expandOptional(prefix + path) {
+			g.addRoute(route, doc, path)
+		}
+	})
+}
+
+func (g *generator) addRoute(route *rux.Route, doc *RouteDoc, routePath string) {
+	path, params := convertPath(routePath, route.Constraints())
+	item := g.doc.Paths[path]
+	if item == nil {
+		item = &PathItem{}
+		g.doc.Paths[path] = item
+	}
+
+	for _, method := range route.Methods() {
+		slot := item.operation(method)
+		if slot == nil || *slot != nil {
+			continue // CONNECT, or registered first by another route
+		}
+		*slot = g.operation(route, doc, method, params)
+	}
+}
+
+// operation builds the Operation of route for method.
+func (g *generator) operation(route *rux.Route, doc *RouteDoc, method string, params []*Parameter) *Operation {
+	op := &Operation{
+		OperationID: doc.OperationID,
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		Tags:        doc.Tags,
+		Deprecated:  doc.Deprecated,
+		Parameters:  append([]*Parameter(nil), params...),
+		Responses:   make(map[string]*Response),
+	}
+	if op.OperationID == "" {
+		op.OperationID = route.Name()
+	}
+	if len(route.Methods()) > 1 && op.OperationID != "" {
+		// Operation IDs must be unique per document.
+		op.OperationID += "_" + strings.ToLower(method)
+	}
+
+	if doc.Request != nil {
+		in, body := g.schemas.request(reflect.TypeOf(doc.Request))
+		op.Parameters = append(op.Parameters, in...)
+		if body != nil && method != http.MethodGet && method != http.MethodHead {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: body}},
+			}
+		}
+	}
+
+	if len(doc.Responses) == 0 {
+		op.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+	statuses := make([]int, 0, len(doc.Responses))
+	for status := range doc.Responses {
+		statuses = append(statuses, status)
+	}
+	// Sorted, so schema names are assigned in a stable order.
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		v := doc.Responses[status]
+		resp := &Response{Description: http.StatusText(status)}
+		if v != nil {
+			resp.Content = map[string]*MediaType{
+				"application/json": {Schema: g.schemas.schemaOf(reflect.TypeOf(v))},
+			}
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+	}
+	return op
+}
+
+// operation returns the field of item for method, nil if OpenAPI has none.
+func (p *PathItem) operation(method string) **Operation {
+	switch method {
+	case http.MethodGet:
+		return &p.Get
+	case http.MethodPut:
+		return &p.Put
+	case http.MethodPost:
+		return &p.Post
+	case http.MethodDelete:
+		return &p.Delete
+	case http.MethodOptions:
+		return &p.Options
+	case http.MethodHead:
+		return &p.Head
+	case http.MethodPatch:
+		return &p.Patch
+	case http.MethodTrace:
+		return &p.Trace
+	}
+	return nil
+}
+
+// routeDoc reads the RouteDoc of route from its metadata.
+func routeDoc(route *rux.Route) *RouteDoc {
+	doc := &RouteDoc{}
+	switch v := route.Opts[OptsKey].(type) {
+	case *RouteDoc:
+		*doc = *v
+	case RouteDoc:
+		*doc = v
+	}
+	if s, ok := route.Opts[OptSummary].(string); ok && doc.Summary == "" {
+		doc.Summary = s
+	}
+	if s, ok := route.Opts[OptDescription].(string); ok && doc.Description == "" {
+		doc.Description = s
+	}
+	if tags, ok := rux.MetaOf[[]string](route, OptTags); ok && len(doc.Tags) == 0 {
+		doc.Tags = tags
+	}
+
+	// Routes registered by rux.AddTyped know their request and response types.
+	if in, out, ok := route.TypedIO(); ok {
+		if doc.Request == nil {
+			doc.Request = reflect.Zero(in).Interface()
+		}
+		if doc.Responses == nil {
+			doc.Responses = map[int]any{http.StatusOK: reflect.Zero(out).Interface()}
+		}
+	}
+	return doc
+}
+
+// expandOptional expands the optional segment of a route path:
+// "/posts[/{id}]" becomes "/posts" and "/posts/{id}".
+func expandOptional(path string) []string {
+	start, end := -1, -1
+	depth := 0
+	for i := 0; i < len(path) && end < 0; i++ {
+		switch path[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '[':
+			if depth == 0 {
+				start = i
+			}
+		case ']':
+			if depth == 0 && start >= 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return []string{path}
+	}
+
+	short := path[:start] + path[end+1:]
+	if short == "" {
+		short = "/"
+	}
+	return []string{short, path[:start] + path[start+1:end] + path[end+1:]}
+}
+
+// convertPath rewrites a rux route path to the OpenAPI template form and
+// returns its path parameters. ":id", "*rest", "{id}" and "{id:regex}"
+// all become "{id}"; regex constraints become the schema pattern.
+func convertPath(path string, constraints map[string]string) (string, []*Parameter) {
+	var b strings.Builder
+	var params []*Parameter
+	for i := 0; i < len(path); {
+		c := path[i]
+		if c == ':' || c == '*' {
+			end := i + 1
+			for end < len(path) && path[end] != '/' {
+				end++
+			}
+			params = append(params, pathParam(path[i+1:end], ""))
+			b.WriteString("{" + path[i+1:end] + "}")
+			i = end
+			continue
+		}
+		if c == '{' {
+			end := closingBrace(path, i)
+			name, pattern, _ := strings.Cut(path[i+1:end], ":")
+			name = strings.TrimSpace(name)
+			if p, ok := constraints[name]; ok {
+				pattern = p
+			}
+			params = append(params, pathParam(name, pattern))
+			b.WriteString("{" + name + "}")
+			i = end + 1
+			continue
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String(), params
+}
+
+// pathParam returns a required string path parameter.
+func pathParam(name, pattern string) *Parameter {
+	schema := &Schema{Type: "string"}
+	if pattern = strings.TrimSpace(pattern); pattern != "" && pattern != ".*" && pattern != ".+" {
+		schema.Pattern = "^(?:" + pattern + ")$"
+	}
+	return &Parameter{Name: name, In: "path", Required: true, Schema: schema}
+}
+
+// closingBrace returns the index of the '}' matching the '{' at start,
+// or the end of s.
+func closingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s) - 1
+}
@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/x/assert"
+	"github.com/gookit/rux/v2"
+)
+
+type user struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name" validate:"required"`
+	Email   string    `json:"email,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+type createUserReq struct {
+	TraceID string `header:"X-Trace-Id"`
+	DryRun  bool   `query:"dry_run"`
+	Name    string `json:"name" validate:"required"`
+	Tags    []string
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func noop(c *rux.Context) {}
+
+func newTestRouter() *rux.Router {
+	r := rux.New()
+	r.GET("/users/{id:\\d+}", noop).Opts = map[string]any{
+		OptSummary: "Show a user",
+		OptTags:    []string{"users"},
+	}
+	Describe(r.AddNamed("user_create", "/users", noop, rux.POST), RouteDoc{
+		Summary:   "Create a user",
+		Tags:      []string{"users"},
+		Request:   createUserReq{},
+		Responses: map[int]any{201: user{}, 422: &errorBody{}, 204: nil},
+	})
+	r.GET("/posts[/{slug}]", noop)
+	r.NewGroup("/files").With(OptTags, []string{"files"}).GET("/{path:.+}", noop)
+	Describe(r.GET("/internal", noop), RouteDoc{Hidden: true})
+
+	sub := rux.New()
+	sub.GET("/", noop)
+	sub.GET("/{id}", noop)
+	r.Mount("/admin", sub)
+	return r
+}
+
+func TestGenerate(t *testing.T) {
+	doc := Generate(newTestRouter(), Info{Title: "Test", Version: "1.0.0"})
+	assert.Eq(t, Version, doc.OpenAPI)
+
+	var paths []string
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	assert.ContainsElems(t, paths, []string{
+		"/users/{id}", "/users", "/posts", "/posts/{slug}", "/files/{path}", "/admin", "/admin/{id}",
+	})
+	assert.NotContains(t, paths, "/internal")
+
+	show := doc.Paths["/users/{id}"].Get
+	assert.Eq(t, "Show a user", show.Summary)
+	assert.Eq(t, []string{"users"}, show.Tags)
+	assert.Len(t, show.Parameters, 1)
+	assert.Eq(t, "path", show.Parameters[0].In)
+	assert.Eq(t, `^(?:\d+)$`, show.Parameters[0].Schema.Pattern)
+	assert.NotNil(t, show.Responses["200"])
+
+	assert.Eq(t, []string{"files"}, doc.Paths["/files/{path}"].Get.Tags)
+
+	create := doc.Paths["/users"].Post
+	assert.Eq(t, "user_create", create.OperationID)
+	assert.Len(t, create.Parameters, 2)
+	assert.Eq(t, "X-Trace-Id", create.Parameters[0].Name)
+	assert.Eq(t, "header", create.Parameters[0].In)
+	assert.Eq(t, "dry_run", create.Parameters[1].Name)
+	assert.Eq(t, "query", create.Parameters[1].In)
+
+	body := create.RequestBody.Content["application/json"].Schema
+	assert.Eq(t, []string{"name"}, body.Required)
+	assert.NotContainsKey(t, body.Properties, "TraceID")
+	assert.ContainsKey(t, body.Properties, "Tags")
+
+	assert.Eq(t, "#/components/schemas/user", create.Responses["201"].Content["application/json"].Schema.Ref)
+	assert.Eq(t, "Created", create.Responses["201"].Description)
+	assert.Nil(t, create.Responses["204"].Content)
+	assert.ContainsKey(t, doc.Components.Schemas, "errorBody")
+
+	u := doc.Components.Schemas["user"]
+	assert.Eq(t, "date-time", u.Properties["created"].Format)
+	assert.Eq(t, []string{"name"}, u.Required)
+
+	assert.Eq(t, "", doc.Paths["/files/{path}"].Get.Parameters[0].Schema.Pattern)
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	first, err := Generate(newTestRouter(), Info{Title: "Test", Version: "1"}).MarshalIndent()
+	assert.NoErr(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := Generate(newTestRouter(), Info{Title: "Test", Version: "1"}).MarshalIndent()
+		assert.NoErr(t, err)
+		assert.Eq(t, string(first), string(again))
+	}
+}
+
+func TestConvertPath(t *testing.T) {
+	path, params := convertPath("/a/:id/b/{slug:[a-z]+}/*rest", map[string]string{"slug": "[a-z]+"})
+	assert.Eq(t, "/a/{id}/b/{slug}/{rest}", path)
+	assert.Len(t, params, 3)
+	assert.Eq(t, "", params[0].Schema.Pattern)
+	assert.Eq(t, "^(?:[a-z]+)$", params[1].Schema.Pattern)
+
+	path, params = convertPath("/@:user-id/{name}.{ext}", nil)
+	assert.Eq(t, "/@{user-id}/{name}.{ext}", path)
+	assert.Len(t, params, 3)
+
+	assert.Eq(t, []string{"/posts", "/posts/{id:\\d{2}}"}, expandOptional("/posts[/{id:\\d{2}}]"))
+	assert.Eq(t, []string{"/", "/{id}"}, expandOptional("[/{id}]"))
+	assert.Eq(t, []string{"/plain"}, expandOptional("/plain"))
+}
+
+func TestServe(t *testing.T) {
+	r := newTestRouter()
+	Serve(r, "/docs", Info{Title: "Test API", Version: "1.0.0"})
+
+	w := testutil.MockRequest(r, "GET", "/docs/openapi.json", nil)
+	assert.Eq(t, 200, w.Code)
+	assert.StrContains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var doc Document
+	assert.NoErr(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Eq(t, "Test API", doc.Info.Title)
+	assert.NotContainsKey(t, doc.Paths, "/docs/openapi.json")
+	assert.NotContainsKey(t, doc.Paths, "/docs")
+
+	w = testutil.MockRequest(r, "GET", "/docs", nil)
+	assert.Eq(t, 200, w.Code)
+	assert.StrContains(t, w.Body.String(), "<title>Test API</title>")
+	assert.StrContains(t, w.Body.String(), `fetch("/docs/openapi.json")`)
+}
+
+type getUserReq struct {
+	ID      int  `path:"id"`
+	Verbose bool `query:"verbose"`
+}
+
+func TestGenerate_TypedHandler(t *testing.T) {
+	r := rux.New()
+	rux.AddTyped(r, "/users/{id}", func(context.Context, *getUserReq) (*user, error) {
+		return nil, nil
+	})
+
+	doc := Generate(r, Info{Title: "Test", Version: "1"})
+	op := doc.Paths["/users/{id}"].Get
+	assert.Len(t, op.Parameters, 2)
+	assert.Eq(t, "id", op.Parameters[0].Name)
+	assert.Eq(t, "verbose", op.Parameters[1].Name)
+	assert.Nil(t, op.RequestBody)
+	assert.Eq(t, "#/components/schemas/user", op.Responses["200"].Content["application/json"].Schema.Ref)
+}
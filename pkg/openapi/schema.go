@@ -0,0 +1,291 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema (draft 2020-12, as used by OpenAPI 3.1).
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	byteSliceType = reflect.TypeOf([]byte{})
+)
+
+// schemaRegistry reflects Go types into schemas. Named struct types are
+// stored once in defs and referenced by $ref.
+type schemaRegistry struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		defs:  make(map[string]*Schema),
+		names: make(map[reflect.Type]string),
+	}
+}
+
+// SchemaOf returns the standalone JSON Schema of the type of v, with all
+// struct types inlined instead of referenced by $ref.
+func SchemaOf(v any) *Schema {
+	reg := newSchemaRegistry()
+	s := reg.schemaOf(reflect.TypeOf(v))
+	return reg.inline(s)
+}
+
+// schemaOf returns the schema of t, a $ref for named struct types.
+func (reg *schemaRegistry) schemaOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawJSONType:
+		return &Schema{}
+	case byteSliceType:
+		return &Schema{Type: "string", Format: "byte"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: reg.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reg.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return reg.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + reg.define(t)}
+	}
+	// interface{} and anything not representable: any value.
+	return &Schema{}
+}
+
+// define registers the named struct type t and returns its schema name.
+func (reg *schemaRegistry) define(t reflect.Type) string {
+	if name, ok := reg.names[t]; ok {
+		return name
+	}
+
+	name := schemaName(t)
+	if _, taken := reg.defs[name]; taken {
+		// Same type name in another package: qualify with the package path.
+		name = strings.NewReplacer("/", "_", ".", "_").Replace(t.PkgPath()) + "_" + name
+	}
+	reg.names[t] = name
+	reg.defs[name] = &Schema{} // placeholder, allows recursive types
+	*reg.defs[name] = *reg.structSchema(t)
+	return name
+}
+
+// schemaName returns the component name of t. Generic instantiations
+// such as Page[pkg.User] become "Page_User".
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		args := name[i+1 : len(name)-1]
+		parts := strings.Split(args, ",")
+		for j, p := range parts {
+			if dot := strings.LastIndexByte(p, '.'); dot >= 0 {
+				p = p[dot+1:]
+			}
+			parts[j] = strings.TrimLeft(p, "*[]")
+		}
+		name = name[:i] + "_" + strings.Join(parts, "_")
+	}
+	return name
+}
+
+// structSchema returns the object schema of the json-encoded fields of t.
+func (reg *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	reg.addFields(s, t, nil)
+	return s
+}
+
+// addFields adds the fields of t to s. skip reports fields handled
+// elsewhere (query / header params); nil keeps all fields.
+func (reg *schemaRegistry) addFields(s *Schema, t reflect.Type, skip func(reflect.StructField) bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitEmpty, ok := jsonField(f)
+		if !ok || (skip != nil && skip(f)) {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				reg.addFields(s, ft, skip)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = reg.schemaOf(f.Type)
+		if isRequired(f, omitEmpty) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// jsonField returns the JSON name of f ("" = Go name) and whether it is
+// encoded at all.
+func jsonField(f reflect.StructField) (name string, omitEmpty, ok bool) {
+	if !f.IsExported() && !f.Anonymous {
+		return "", false, false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(opts, "omitempty"), true
+}
+
+// isRequired reports whether f is marked required by a validate:"required"
+// or binding:"required" rule. omitempty fields are never required.
+func isRequired(f reflect.StructField, omitEmpty bool) bool {
+	if omitEmpty {
+		return false
+	}
+	for _, key := range []string{"validate", "binding"} {
+		for _, rule := range strings.Split(f.Tag.Get(key), "|") {
+			for _, r := range strings.Split(rule, ",") {
+				if strings.TrimSpace(r) == "required" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// request splits the request type t into query / header parameters and
+// the JSON body schema (nil when every field is a parameter).
+func (reg *schemaRegistry) request(t reflect.Type) (params []*Parameter, body *Schema) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, reg.schemaOf(t)
+	}
+
+	// path:"..." fields are bound from the route params, which are
+	// documented from the route path.
+	isParam := func(f reflect.StructField) bool {
+		return f.Tag.Get("query") != "" || f.Tag.Get("header") != "" || f.Tag.Get("path") != ""
+	}
+	params = reg.params(t)
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	reg.addFields(s, t, isParam)
+	if len(s.Properties) == 0 {
+		return params, nil
+	}
+	if len(params) == 0 && t.Name() != "" {
+		// Plain body type: reference the shared component.
+		return nil, reg.schemaOf(t)
+	}
+	return params, s
+}
+
+// params returns the query and header parameters of the struct type t.
+func (reg *schemaRegistry) params(t reflect.Type) []*Parameter {
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				params = append(params, reg.params(ft)...)
+			}
+			continue
+		}
+		for _, in := range []string{"query", "header"} {
+			name, _, _ := strings.Cut(f.Tag.Get(in), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			params = append(params, &Parameter{
+				Name:     name,
+				In:       in,
+				Required: isRequired(f, false),
+				Schema:   reg.schemaOf(f.Type),
+			})
+		}
+	}
+	return params
+}
+
+// inline replaces $refs in s by copies of the referenced definitions.
+func (reg *schemaRegistry) inline(s *Schema) *Schema {
+	return reg.inlineSeen(s, map[string]bool{})
+}
+
+func (reg *schemaRegistry) inlineSeen(s *Schema, seen map[string]bool) *Schema {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		if seen[name] {
+			return &Schema{Type: "object"} // recursive type
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return reg.inlineSeen(reg.defs[name], seen)
+	}
+
+	out := *s
+	out.Items = reg.inlineSeen(s.Items, seen)
+	out.AdditionalProperties = reg.inlineSeen(s.AdditionalProperties, seen)
+	if s.Properties != nil {
+		out.Properties = make(map[string]*Schema, len(s.Properties))
+		for k, v := range s.Properties {
+			out.Properties[k] = reg.inlineSeen(v, seen)
+		}
+	}
+	return &out
+}
@@ -0,0 +1,59 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gookit/goutil/x/assert"
+)
+
+type base struct {
+	ID uint `json:"id"`
+}
+
+type node struct {
+	base
+	Name     string            `json:"name,omitempty" validate:"required"`
+	Children []*node           `json:"children"`
+	Labels   map[string]string `json:"labels"`
+	Raw      json.RawMessage   `json:"raw"`
+	Data     []byte            `json:"data"`
+	Any      any               `json:"any"`
+	Skipped  string            `json:"-"`
+	private  string
+}
+
+type page[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	assert.Eq(t, "boolean", SchemaOf(true).Type)
+	assert.Eq(t, "int64", SchemaOf(int64(1)).Format)
+	assert.Eq(t, "double", SchemaOf(1.5).Format)
+	assert.Eq(t, "array", SchemaOf([]string{}).Type)
+
+	s := SchemaOf(&node{})
+	assert.Eq(t, "object", s.Type)
+	assert.ContainsKeys(t, s.Properties, []string{"id", "name", "children", "labels", "raw", "data", "any"})
+	assert.NotContainsKey(t, s.Properties, "Skipped")
+	assert.NotContainsKey(t, s.Properties, "private")
+	// omitempty wins over validate:"required".
+	assert.Nil(t, s.Required)
+
+	assert.Eq(t, 0.0, *s.Properties["id"].Minimum)
+	assert.Eq(t, "string", s.Properties["labels"].AdditionalProperties.Type)
+	assert.Eq(t, "byte", s.Properties["data"].Format)
+	assert.Eq(t, "", s.Properties["raw"].Type)
+	// The recursive reference is cut off after one level.
+	assert.Eq(t, "object", s.Properties["children"].Items.Type)
+}
+
+func TestSchemaRegistry_Refs(t *testing.T) {
+	reg := newSchemaRegistry()
+	s := reg.schemaOf(reflect.TypeOf(page[node]{}))
+	assert.Eq(t, "#/components/schemas/page_node", s.Ref)
+	assert.ContainsKeys(t, reg.defs, []string{"page_node", "node"})
+	assert.Eq(t, "#/components/schemas/node", reg.defs["node"].Properties["children"].Items.Ref)
+}
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gookit/rux/v2"
+)
+
+// Handler returns a handler serving the document of r as JSON. The
+// document is generated per request, so it follows the active route set
+// (see Router.Swap).
+func Handler(r *rux.Router, info Info) rux.HandlerFunc {
+	return func(c *rux.Context) {
+		bs, err := Generate(r, info).MarshalIndent()
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Blob(http.StatusOK, "application/json; charset=utf-8", bs)
+	}
+}
+
+// DocsHandler returns a handler serving a self-contained HTML page that
+// lists the operations of the document at specURL. It loads no external
+// scripts or styles.
+func DocsHandler(title, specURL string) rux.HandlerFunc {
+	var buf bytes.Buffer
+	_ = docsTpl.Execute(&buf, map[string]string{"Title": title, "SpecURL": specURL})
+	page := buf.Bytes()
+
+	return func(c *rux.Context) {
+		c.HTML(http.StatusOK, page)
+	}
+}
+
+// Serve registers the document at prefix+"/openapi.json" and the docs
+// page at prefix, both as GET routes hidden from the document.
+//
+//	openapi.Serve(r, "/docs", openapi.Info{Title: "Shop API", Version: "1.0.0"})
+func Serve(r *rux.Router, prefix string, info Info) {
+	prefix = strings.TrimRight(prefix, "/")
+	specURL := prefix + "/openapi.json"
+	hidden := RouteDoc{Hidden: true}
+
+	Describe(r.GET(specURL, Handler(r, info)), hidden)
+	Describe(r.GET(prefix+"/", DocsHandler(info.Title, specURL)), hidden)
+}
+
+var docsTpl = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+.op { border: 1px solid #ddd; border-radius: 4px; margin: .5em 0; padding: .5em .8em; }
+.method { display: inline-block; min-width: 5em; font-weight: bold; text-transform: uppercase; }
+.summary { color: #666; margin-left: 1em; }
+pre { background: #f6f8fa; padding: .5em; overflow: auto; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><a href="{{.SpecURL}}">{{.SpecURL}}</a></p>
+<div id="ops">Loading...</div>
+<script>
+fetch({{.SpecURL}}).then(function (res) { return res.json(); }).then(function (doc) {
+  var root = document.getElementById("ops");
+  root.textContent = "";
+  Object.keys(doc.paths).sort().forEach(function (path) {
+    var item = doc.paths[path];
+    Object.keys(item).forEach(function (method) {
+      var op = item[method];
+      var el = document.createElement("details");
+      el.className = "op";
+      var head = document.createElement("summary");
+      var m = document.createElement("span");
+      m.className = "method";
+      m.textContent = method;
+      var s = document.createElement("span");
+      s.className = "summary";
+      s.textContent = op.summary || "";
+      head.append(m, path, s);
+      var body = document.createElement("pre");
+      body.textContent = JSON.stringify(op, null, 2);
+      el.append(head, body);
+      root.append(el);
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -8,6 +8,8 @@
 package rux
 
 import (
+	"context"
+
 	"github.com/gookit/rux/v2/internal/core"
 )
 
@@ -37,11 +39,22 @@ const (
 	CTXRecoverResult  = core.CTXRecoverResult
 )
 
+// RFC 7807 problem details content types.
+const (
+	ProblemJSON = core.ProblemJSON
+	ProblemXML  = core.ProblemXML
+)
+
+// MaxParams is the default number of path params a route may have, see
+// ParamsLimit.
+const MaxParams = core.MaxParams
+
 // Public types — all aliased to the internal/core implementation.
 type (
 	Router          = core.Router
 	Context         = core.Context
 	Route           = core.Route
+	RouteGroup      = core.RouteGroup
 	HandlerFunc     = core.HandlerFunc
 	HandlersChain   = core.HandlersChain
 	Param           = core.Param
@@ -52,6 +65,35 @@ type (
 	Renderer        = core.Renderer
 	Validator       = core.Validator
 	ControllerFace  = core.ControllerFace
+	GroupController = core.GroupController
+	ResourceOptions = core.ResourceOptions
+	Resource        = core.Resource
+	StatusCoder     = core.StatusCoder
+	RouteAdder      = core.RouteAdder
+	HTTPError       = core.HTTPError
+	Problem         = core.Problem
+	Analysis        = core.Analysis
+	RouteIssue      = core.RouteIssue
+	RouteSample     = core.RouteSample
+	IssueKind       = core.IssueKind
+	Explanation     = core.Explanation
+	ExplainStep     = core.ExplainStep
+	ParamError      = core.ParamError
+	Condition       = core.Condition
+	APIVersions     = core.APIVersions
+	VersionOption   = core.VersionOption
+	Deprecation     = core.Deprecation
+	VariantKey      = core.VariantKey
+	ExportedRoute   = core.ExportedRoute
+	ExportedRoutes  = core.ExportedRoutes
+	HandlerRegistry = core.HandlerRegistry
+)
+
+// Route analysis issue kinds, see Router.Analyze.
+const (
+	IssueAmbiguous   = core.IssueAmbiguous
+	IssueShadowed    = core.IssueShadowed
+	IssueUnreachable = core.IssueUnreachable
 )
 
 // REST action names.
@@ -76,6 +118,7 @@ var (
 	AnyMethods         = core.AnyMethods
 	AllMethods         = core.AllMethods
 	MethodsString      = core.MethodsString
+	ParseRoutes        = core.ParseRoutes
 )
 
 // Router options.
@@ -84,7 +127,35 @@ var (
 	UseEncodedPath         = core.UseEncodedPath
 	HandleMethodNotAllowed = core.HandleMethodNotAllowed
 	HandleFallbackRoute    = core.HandleFallbackRoute
+	RedirectTrailingSlash  = core.RedirectTrailingSlash
+	RedirectFixedPath      = core.RedirectFixedPath
+	HandleOptions          = core.HandleOptions
 	InterceptAll           = core.InterceptAll
+	ParamsLimit            = core.ParamsLimit
+)
+
+// Route conditions, see Router.When.
+var (
+	HeaderIs      = core.HeaderIs
+	HeaderMatches = core.HeaderMatches
+	HasQuery      = core.HasQuery
+	QueryIs       = core.QueryIs
+	ContentTypeIs = core.ContentTypeIs
+	MatchFunc     = core.MatchFunc
+)
+
+// Weighted route variant keys, see Router.Variant.
+var (
+	ByCookie   = core.ByCookie
+	ByHeader   = core.ByHeader
+	ByClientIP = core.ByClientIP
+)
+
+// API version selection, see Router.Versions.
+var (
+	VersionByPath   = core.VersionByPath
+	VersionByHeader = core.VersionByHeader
+	VersionByAccept = core.VersionByAccept
 )
 
 // Middleware adapters (wrap http.Handler / http.HandlerFunc as HandlerFunc).
@@ -96,3 +167,44 @@ var (
 	HTTPHandlerFunc     = core.HTTPHandlerFunc
 	WrapHTTPHandlerFunc = core.WrapHTTPHandlerFunc
 )
+
+// Error helpers. ProblemHandler is the default Router.OnError.
+var (
+	NewHTTPError          = core.NewHTTPError
+	ProblemHandler        = core.ProblemHandler
+	BindErrorMapper       = core.BindErrorMapper
+	ValidationErrorMapper = core.ValidationErrorMapper
+	ErrParamMissing       = core.ErrParamMissing
+	ErrUnknownRoute       = core.ErrUnknownRoute
+)
+
+// ParseParam parses the named path param with parse. A missing param or
+// a parse error is returned as a *ParamError (400 Bad Request).
+func ParseParam[T any](p *Params, name string, parse func(string) (T, error)) (T, error) {
+	return core.ParseParam(p, name, parse)
+}
+
+// Meta returns the metadata value of key of the route matched by c,
+// converted to T. See Route.With.
+func Meta[T any](c *Context, key string) (T, bool) {
+	return core.Meta[T](c, key)
+}
+
+// MetaOf returns the metadata value of key of route, converted to T.
+func MetaOf[T any](route *Route, key string) (T, bool) {
+	return core.MetaOf[T](route, key)
+}
+
+// Typed adapts a typed handler func to a HandlerFunc: the request is
+// bound into In and validated, the returned Out is rendered by content
+// negotiation and errors become HTTP statuses. See AddTyped.
+func Typed[In, Out any](fn func(ctx context.Context, in *In) (*Out, error)) HandlerFunc {
+	return core.Typed(fn)
+}
+
+// AddTyped registers fn, adapted by Typed, on ra and records In and Out
+// on the route, see Route.TypedIO.
+func AddTyped[In, Out any](ra RouteAdder, path string, fn func(ctx context.Context, in *In) (*Out, error),
+	methods ...string) *Route {
+	return core.AddTyped(ra, path, fn, methods...)
+}
@@ -114,12 +114,12 @@ func New(debugMode bool) *Server {
 		r.Use(handlers.RequestLogger())
 	}
 
-	// Default error handler — keeps backward compatibility with the v1 stub.
+	// Default error handler — logs the first error and renders it as
+	// problem details.
 	r.OnError = func(c *rux.Context) {
 		if err := c.FirstError(); err != nil {
 			ccolor.Errorln("Server error: ", err)
-			c.HTTPError(err.Error(), 400)
-			return
+			rux.ProblemHandler(c)
 		}
 	}
 